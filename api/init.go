@@ -1,14 +1,40 @@
 package api
 
 import (
+	"context"
+	"goMahjong/config"
 	"goMahjong/handler"
 	"goMahjong/service"
+	"goMahjong/store"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 )
 
-func Init(engine *gin.Engine) {
-	gameManager := service.NewGameManager()
+// Init 打开存储、恢复房间并注册路由，返回的游戏管理器用于停服
+// ctx 结束时停止定期保存检查点
+func Init(ctx context.Context, engine *gin.Engine) *service.GameManager {
+	st, err := store.Open(viper.GetString("store.driver"), viper.GetString("store.path"))
+	if err != nil {
+		config.GetZapLogger().Fatal("打开存储失败: " + err.Error())
+	}
+	gameManager := service.NewGameManager(st)
+
+	// 恢复服务停止前的房间，定期保存进行中的房间
+	for _, room := range gameManager.RestoreRooms() {
+		handler.HoldRestoredSeats(room, gameManager)
+	}
+	gameManager.StartCheckpoints(ctx, viper.GetDuration("store.checkpointInterval"))
+	gameManager.StartJanitor(ctx, viper.GetDuration("room.janitorInterval"))
+	joinLimiter := service.NewAttemptLimiter(
+		viper.GetInt("security.joinMaxFailures"),
+		viper.GetInt("security.joinMaxFailuresPerIP"),
+		viper.GetDuration("security.joinFailureWindow"),
+	)
+	// 按IP的限制都依赖客户端IP，只有信任的代理转发的 X-Forwarded-For 才能用，否则客户端可以随意伪造
+	if err := engine.SetTrustedProxies(viper.GetStringSlice("security.trustedProxies")); err != nil {
+		config.GetZapLogger().Fatal("信任的代理地址配置错误: " + err.Error())
+	}
 	// 设置静态文件目录
 	engine.Static("/static", "./static")
 	engine.LoadHTMLGlob("templates/*")
@@ -18,6 +44,7 @@ func Init(engine *gin.Engine) {
 	engine.GET("/room/:roomID", handler.RoomHandler)
 	engine.GET("/create", handler.CreateRoomHandler)
 	engine.GET("/join", handler.JoinRoomHandler)
+	engine.GET("/replay/:replayID", handler.ReplayHandler)
 
 	// WebSocket连接
 	engine.GET("/ws/:roomID", func(c *gin.Context) {
@@ -28,7 +55,13 @@ func Init(engine *gin.Engine) {
 	api := engine.Group("/api")
 	{
 		api.GET("/rooms", handler.GetRoomsHandler(gameManager))
+		api.GET("/protocol", handler.ProtocolHandler)
+		api.GET("/replays/:replayID", handler.ReplayAPIHandler(gameManager))
+		api.GET("/replays/:replayID/mjai", handler.ExportMJAIHandler(gameManager))
+		api.POST("/replays/import", handler.ImportMJAIHandler(gameManager))
 		api.POST("/room/create", handler.CreateRoomAPIHandler(gameManager))
-		api.POST("/room/join", handler.JoinRoomAPIHandler(gameManager))
+		api.POST("/room/join", handler.JoinRoomAPIHandler(gameManager, joinLimiter))
+		api.POST("/room/spectate", handler.SpectateRoomAPIHandler(gameManager, joinLimiter))
 	}
+	return gameManager
 }
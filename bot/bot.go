@@ -0,0 +1,122 @@
+// Package bot 内置的机器人玩家
+//
+// 机器人和WebSocket玩家一样实现 transport.Client：房间把消息发给它，它根据自己视角的
+// 对局快照做决定，再通过 Table 把出牌、定缺、碰杠胡的请求交给房间的命令处理流程，
+// 所以机器人不能做任何真人玩家做不到的事
+package bot
+
+import (
+	"goMahjong/model"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Table 机器人所在的牌桌，由 handler 实现
+type Table interface {
+	// Snapshot 返回机器人视角的对局快照和房间当前序号，对局不在进行中或机器人已离开时 ok 为false
+	Snapshot() (view model.GameSnapshotData, seq uint64, ok bool)
+	// Submit 提交一个请求，和客户端发来的请求走同样的处理流程
+	Submit(msgType string, req interface{}, seq uint64)
+}
+
+// Bot 一个机器人玩家
+type Bot struct {
+	playerID  string
+	strategy  Strategy
+	table     Table
+	thinkTime time.Duration // 每次行动前的停顿，让真人看得清机器人的操作
+
+	wake      chan struct{} // 收到消息后唤醒决策协程，多条消息合并成一次
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New 创建机器人并启动决策协程
+func New(playerID string, strategy Strategy, table Table) *Bot {
+	b := &Bot{
+		playerID:  playerID,
+		strategy:  strategy,
+		table:     table,
+		thinkTime: viper.GetDuration("bot.thinkTime"),
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Send 接收房间发来的消息，只唤醒决策协程，不会阻塞
+// 错误消息不会改变对局状态，等下一条消息再重新决策
+func (b *Bot) Send(message model.Message) error {
+	if message.Type == model.MsgError {
+		return nil
+	}
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Close 停止机器人，可重复调用
+func (b *Bot) Close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+}
+
+func (b *Bot) run() {
+	for {
+		select {
+		case <-b.wake:
+		case <-b.done:
+			return
+		}
+		if b.thinkTime > 0 {
+			select {
+			case <-time.After(b.thinkTime):
+			case <-b.done:
+				return
+			}
+		}
+		b.act()
+	}
+}
+
+// act 按当前快照做一次决定，没有需要自己做的事时什么都不做
+func (b *Bot) act() {
+	snapshot, seq, ok := b.table.Snapshot()
+	if !ok {
+		return
+	}
+	view := &View{GameSnapshotData: snapshot, PlayerID: b.playerID}
+
+	switch view.Phase {
+	case model.PhaseDingque:
+		if view.Missing() == "" {
+			b.table.Submit(model.ReqDingque, &model.DingqueRequest{Suit: b.strategy.Dingque(view)}, seq)
+		}
+	case model.PhaseClaim:
+		if len(view.Options) > 0 {
+			action := b.strategy.Claim(view)
+			b.table.Submit(model.ReqAction, &model.ActionRequest{Action: action}, seq)
+		}
+	case model.PhaseDiscard:
+		if view.CurrentPlayerID != b.playerID {
+			return
+		}
+		if len(view.Options) > 0 {
+			if action, tile := b.strategy.Turn(view); action != "" {
+				req := &model.ActionRequest{Action: action}
+				if tile != "" {
+					req.Tiles = []string{tile}
+				}
+				b.table.Submit(model.ReqAction, req, seq)
+				return
+			}
+		}
+		b.table.Submit(model.ReqPlayTile, &model.PlayTileRequest{Tile: b.strategy.Discard(view)}, seq)
+	}
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"goMahjong/model"
+	"goMahjong/rules"
+	"goMahjong/transport"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roomTable 直接调用房间方法的牌桌，代替 handler 的命令处理流程
+type roomTable struct {
+	room     *model.Room
+	playerID string
+	mu       *sync.Mutex
+}
+
+func (t *roomTable) Snapshot() (model.GameSnapshotData, uint64, bool) {
+	t.room.Lock()
+	defer t.room.Unlock()
+	if t.room.GameState != model.GameStatePlaying {
+		return model.GameSnapshotData{}, 0, false
+	}
+	return t.room.SeatView(t.playerID), t.room.Seq(), true
+}
+
+func (t *roomTable) Submit(msgType string, req interface{}, seq uint64) {
+	t.room.Lock()
+	defer t.room.Unlock()
+	if err := t.room.CheckFresh(seq); err != nil {
+		return
+	}
+	switch req := req.(type) {
+	case *model.DingqueRequest:
+		t.room.HandleDingque(t.playerID, req.Suit)
+	case *model.PlayTileRequest:
+		t.room.HandlePlayTile(t.playerID, req.Tile)
+	case *model.ActionRequest:
+		t.room.HandlePlayerAction(t.playerID, req.Action, req.Tiles)
+	}
+}
+
+func TestBotsPlayAFullHand(t *testing.T) {
+	viper.Set("bot.thinkTime", time.Duration(0))
+	defer viper.Set("bot.thinkTime", nil)
+
+	for players := 2; players <= model.MaxPlayers; players++ {
+		room, err := model.NewRoom("")
+		require.NoError(t, err)
+		clients := transport.NewClients()
+		room.Transport = clients
+		finished := make(chan *model.Replay, 1)
+		room.OnHandFinished = func(r *model.Replay) error {
+			finished <- r
+			return nil
+		}
+
+		// 每个座位用不同的难度，专家的模拟次数减少以加快测试
+		strategies := []Strategy{
+			&MonteCarlo{rng: rand.New(rand.NewSource(1)), Playouts: 3},
+			Defensive{},
+			Greedy{},
+			&Random{rng: rand.New(rand.NewSource(2))},
+		}
+		room.Lock()
+		for i := 0; i < players; i++ {
+			p := model.NewPlayer(string(rune('A' + i)))
+			p.Bot = true
+			room.AddPlayer(p)
+			b := New(p.ID, strategies[i], &roomTable{room: room, playerID: p.ID})
+			defer b.Close()
+			clients.Attach(p.ID, b)
+		}
+		room.SetOwner(room.Players[0])
+		require.NoError(t, room.StartGameBy(room.Owner.ID))
+		room.Unlock()
+
+		select {
+		case replay := <-finished:
+			assert.Len(t, replay.Seats, players)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%d 个机器人没有打完一局", players)
+		}
+	}
+}
+
+func view(hand string, missing string, options ...string) *View {
+	v := &View{PlayerID: "me"}
+	v.Players = []model.SeatInfo{{ID: "me", Missing: missing}}
+	v.Tiles = strings.Fields(hand)
+	v.Options = options
+	return v
+}
+
+func TestGreedyDecisions(t *testing.T) {
+	g := Greedy{}
+
+	// 缺门的牌先打
+	assert.Equal(t, "9w", g.Discard(view("1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p 9w", "w")))
+	// 打出孤张，留下搭子
+	assert.Equal(t, "9p", g.Discard(view("1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 5p 5p 9p", "w")))
+	// 定缺最少的花色
+	assert.Equal(t, rules.SuitWan, g.Dingque(view("1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5w", "")))
+
+	// 能胡就胡，碰之后向听数变小才碰
+	claim := view("1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p", "w", model.ActionHu, model.ActionPass)
+	claim.LastPlayedTile = "5p"
+	assert.Equal(t, model.ActionHu, g.Claim(claim))
+	peng := view("1t 2t 3t 4t 5t 6t 5p 5p 8p 8p 1p 9t 3p", "w", model.ActionPeng, model.ActionPass)
+	peng.LastPlayedTile = "5p"
+	assert.Equal(t, model.ActionPeng, g.Claim(peng))
+	pass := view("1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 5p 5p", "w", model.ActionPeng, model.ActionPass)
+	pass.LastPlayedTile = "5p"
+	assert.Equal(t, model.ActionPass, g.Claim(pass))
+
+	// 暗杠不影响听牌时杠
+	action, tile := g.Turn(view("1t 1t 1t 1t 2t 3t 4t 5p 6p 7p 8p 8p 9p 9p", "w", model.ActionGang))
+	assert.Equal(t, model.ActionGang, action)
+	assert.Equal(t, "1t", tile)
+}
+
+func TestNewStrategy(t *testing.T) {
+	for _, level := range append(Levels, "") {
+		s, err := NewStrategy(level, 1)
+		require.NoError(t, err, level)
+		assert.NotNil(t, s, level)
+	}
+	_, err := NewStrategy("impossible", 1)
+	assert.ErrorIs(t, err, ErrUnknownLevel)
+}
+
+func TestDefensiveFoldsAgainstAThreat(t *testing.T) {
+	// 自己还差两张以上，下家碰了两次、打了很多牌，缺门是万
+	v := view("1t 4t 7t 2p 5p 8p 3p 6p 9p 3t 6t 9t 2t 7p", rules.SuitWan)
+	calm := *v
+	v.Players = append(v.Players, model.SeatInfo{
+		ID:       "next",
+		Missing:  rules.SuitWan,
+		Melds:    []rules.Meld{{Kind: rules.MeldPeng, Tile: "5t"}, {Kind: rules.MeldPeng, Tile: "5p"}},
+		Discards: strings.Fields("1p 9t 2t 8p 7p 4p 1t 3t 9p 8t"),
+	})
+
+	// 对方打过的牌最安全
+	d := Defensive{}
+	tile := d.Discard(v)
+	assert.Contains(t, []string{"1t", "9t", "2t", "9p", "3t", "7p"}, tile)
+	assert.Equal(t, model.ActionPass, d.Claim(withOptions(v, "3p", model.ActionPeng, model.ActionPass)))
+
+	// 没有威胁时和 Greedy 一样进攻
+	assert.Equal(t, Greedy{}.Discard(&calm), d.Discard(&calm))
+}
+
+func withOptions(v *View, tile string, options ...string) *View {
+	claim := *v
+	claim.Tiles = claim.Tiles[:13]
+	claim.LastPlayedTile = tile
+	claim.Options = options
+	return &claim
+}
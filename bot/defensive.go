@@ -0,0 +1,159 @@
+package bot
+
+import (
+	"goMahjong/model"
+	"goMahjong/rules"
+)
+
+// 弃和的条件：有对手听牌的可能性超过 readyThreshold 且自己还差两张以上，
+// 或者可能性超过 certainThreshold 且自己还没听牌
+const (
+	readyThreshold   = 0.6
+	certainThreshold = 0.8
+)
+
+// Defensive 防守策略：在贪心的基础上看对手的弃牌和副露，估计每个对手听牌的可能性
+// 和每张牌对他的危险程度；对手可能听牌而自己离听牌还远时弃和，不再碰杠，只打安全的牌
+type Defensive struct {
+	Greedy
+}
+
+// Claim 能胡就胡，弃和时不碰不杠
+func (d Defensive) Claim(v *View) string {
+	if contains(v.Options, model.ActionHu) {
+		return model.ActionHu
+	}
+	if shouldFold(rules.Shanten(v.Tiles, v.Missing()), threats(v)) {
+		return model.ActionPass
+	}
+	return d.Greedy.Claim(v)
+}
+
+// Turn 能自摸就胡，弃和时不杠
+func (d Defensive) Turn(v *View) (string, string) {
+	if contains(v.Options, model.ActionHu) {
+		return model.ActionHu, ""
+	}
+	if shouldFold(bestShanten(v.Tiles, v.Missing()), threats(v)) {
+		return "", ""
+	}
+	return d.Greedy.Turn(v)
+}
+
+// Discard 弃和时打最安全的牌；否则在向听数最小的牌中，进张差不多时选危险小的
+func (d Defensive) Discard(v *View) string {
+	options := rankDiscards(v)
+	threats := threats(v)
+	if shouldFold(options[0].shanten, threats) {
+		return safest(v, options, threats)
+	}
+	return pushSafely(v, options, threats)
+}
+
+// threats 每个对手听牌的可能性，键为对手的玩家ID
+func threats(v *View) map[string]float64 {
+	result := make(map[string]float64)
+	for _, seat := range v.Players {
+		if seat.ID != v.PlayerID {
+			result[seat.ID] = threat(seat)
+		}
+	}
+	return result
+}
+
+// threat 估计对手听牌的可能性（0到1）：副露越多、打出的牌越多越可能听牌；
+// 最近还在打缺门的牌说明离听牌还远
+func threat(seat model.SeatInfo) float64 {
+	if seat.Missing == "" {
+		return 0
+	}
+	recent := seat.Discards
+	if len(recent) > 3 {
+		recent = recent[len(recent)-3:]
+	}
+	if rules.CountSuit(recent, seat.Missing) > 0 {
+		return 0.1
+	}
+	t := 0.15*float64(len(seat.Melds)) + 0.04*float64(len(seat.Discards))
+	if t > 1 {
+		t = 1
+	}
+	return t
+}
+
+// shouldFold 是否弃和
+func shouldFold(shanten int, threats map[string]float64) bool {
+	for _, t := range threats {
+		if (t >= certainThreshold && shanten >= 1) || (t >= readyThreshold && shanten >= 2) {
+			return true
+		}
+	}
+	return false
+}
+
+// danger 打出这张牌的危险程度：每个对手听牌的可能性乘以这张牌对他的危险程度
+func danger(v *View, tile string, threats map[string]float64) float64 {
+	total := 0.0
+	for _, seat := range v.Players {
+		if t := threats[seat.ID]; t > 0 {
+			total += t * tileDanger(v, seat, tile)
+		}
+	}
+	return total
+}
+
+// tileDanger 这张牌对某个对手的危险程度（0到1）
+// 缺门的牌对方不能胡；幺九牌能组成的顺子少；对方打过的牌、看得到三张的牌、
+// 对方打过隔三张的筋牌都相对安全
+func tileDanger(v *View, seat model.SeatInfo, tile string) float64 {
+	suit := rules.Suit(tile)
+	if suit == seat.Missing {
+		return 0
+	}
+
+	d := 1.0
+	switch rules.Rank(tile) {
+	case 1, 9:
+		d = 0.5
+	case 2, 8:
+		d = 0.8
+	}
+	if rules.Count(seat.Discards, tile) > 0 {
+		d *= 0.3
+	}
+	if v.Visible(tile) >= 3 {
+		d *= 0.3
+	}
+	for _, discard := range seat.Discards {
+		if rules.Suit(discard) == suit && (rules.Rank(discard) == rules.Rank(tile)+3 || rules.Rank(discard) == rules.Rank(tile)-3) {
+			d *= 0.6
+			break
+		}
+	}
+	return d
+}
+
+// safest 最安全的牌，一样安全时选向听数小的
+func safest(v *View, options []discardOption, threats map[string]float64) string {
+	best, minDanger := options[0].tile, danger(v, options[0].tile, threats)
+	for _, o := range options[1:] {
+		if d := danger(v, o.tile, threats); d < minDanger {
+			best, minDanger = o.tile, d
+		}
+	}
+	return best
+}
+
+// pushSafely 向听数最小、进张不少于最多进张四分之三的牌中危险最小的
+func pushSafely(v *View, options []discardOption, threats map[string]float64) string {
+	best, minDanger := options[0].tile, danger(v, options[0].tile, threats)
+	for _, o := range options[1:] {
+		if o.shanten > options[0].shanten || o.ukeire*4 < options[0].ukeire*3 {
+			continue
+		}
+		if d := danger(v, o.tile, threats); d < minDanger {
+			best, minDanger = o.tile, d
+		}
+	}
+	return best
+}
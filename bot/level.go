@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"errors"
+	"goMahjong/model"
+	"goMahjong/rules"
+	"math/rand"
+)
+
+// 机器人难度
+const (
+	LevelEasy   = "easy"   // 随机打出合法的牌
+	LevelNormal = "normal" // 贪心，只看自己的向听数和进张
+	LevelHard   = "hard"   // 看别人的弃牌和副露判断危险牌，对手可能听牌时弃和
+	LevelExpert = "expert" // 在 hard 的基础上用蒙特卡洛模拟比较出牌
+)
+
+// Levels 所有难度，从易到难
+var Levels = []string{LevelEasy, LevelNormal, LevelHard, LevelExpert}
+
+// ErrUnknownLevel 未知的难度
+var ErrUnknownLevel = errors.New("unknown bot level")
+
+// NewStrategy 按难度创建策略，seed 用于随机和模拟，空难度为 normal
+func NewStrategy(level string, seed int64) (Strategy, error) {
+	rng := rand.New(rand.NewSource(seed))
+	switch level {
+	case LevelEasy:
+		return &Random{rng: rng}, nil
+	case LevelNormal, "":
+		return Greedy{}, nil
+	case LevelHard:
+		return Defensive{}, nil
+	case LevelExpert:
+		return &MonteCarlo{rng: rng, Playouts: defaultPlayouts}, nil
+	}
+	return nil, ErrUnknownLevel
+}
+
+// Random 随机策略：在合法的动作和牌中随便选
+type Random struct {
+	rng *rand.Rand
+}
+
+// Dingque 随机定缺
+func (r *Random) Dingque(v *View) string {
+	return rules.Suits[r.rng.Intn(len(rules.Suits))]
+}
+
+// Claim 随机选一个可以执行的动作
+func (r *Random) Claim(v *View) string {
+	return v.Options[r.rng.Intn(len(v.Options))]
+}
+
+// Turn 随机决定是否自摸或杠
+func (r *Random) Turn(v *View) (string, string) {
+	choice := r.rng.Intn(len(v.Options) + 1)
+	if choice == len(v.Options) {
+		return "", ""
+	}
+	switch action := v.Options[choice]; action {
+	case model.ActionGang:
+		tiles := gangTiles(v)
+		if len(tiles) == 0 {
+			return "", ""
+		}
+		return action, tiles[r.rng.Intn(len(tiles))]
+	default:
+		return action, ""
+	}
+}
+
+// Discard 随机打出一张合法的牌，缺门的牌先打
+func (r *Random) Discard(v *View) string {
+	candidates := discardCandidates(v.Tiles, v.Missing())
+	return candidates[r.rng.Intn(len(candidates))]
+}
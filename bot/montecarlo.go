@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"goMahjong/rules"
+	"math/rand"
+)
+
+const (
+	defaultPlayouts = 40  // 每个候选出牌模拟的次数
+	mcCandidates    = 4   // 参与模拟的候选出牌数
+	mcHorizon       = 10  // 每次模拟最多摸几张牌
+	mcDangerWeight  = 0.2 // 危险程度换算成胡牌率的权重
+)
+
+// MonteCarlo 蒙特卡洛策略：碰杠和弃和与 Defensive 相同；出牌时对排名靠前的几张候选牌，
+// 用还没看到的牌随机模拟之后的摸牌，比较在剩下的巡目里自摸的比例，再扣掉放炮的危险
+type MonteCarlo struct {
+	Defensive
+	rng      *rand.Rand
+	Playouts int // 每个候选出牌模拟的次数
+}
+
+// Discard 选模拟中胡牌率减去危险程度最高的牌
+func (m *MonteCarlo) Discard(v *View) string {
+	options := rankDiscards(v)
+	threats := threats(v)
+	if shouldFold(options[0].shanten, threats) {
+		return safest(v, options, threats)
+	}
+
+	missing := v.Missing()
+	unseen := unseenTiles(v)
+	draws := v.RemainingTiles/len(v.Players) + 1
+	if draws > mcHorizon {
+		draws = mcHorizon
+	}
+
+	best, bestScore := options[0].tile, -1.0
+	for i, o := range options {
+		if i == mcCandidates || o.shanten > options[0].shanten+1 {
+			break
+		}
+		hand, _ := rules.Remove(v.Tiles, o.tile, 1)
+		wins := 0
+		for n := 0; n < m.Playouts; n++ {
+			if m.playout(hand, missing, unseen, draws) {
+				wins++
+			}
+		}
+		score := float64(wins)/float64(m.Playouts) - mcDangerWeight*danger(v, o.tile, threats)
+		if score > bestScore {
+			best, bestScore = o.tile, score
+		}
+	}
+	return best
+}
+
+// playout 从还没看到的牌中随机摸 draws 张，每次摸牌后打出让向听数最小的牌，返回能否自摸
+func (m *MonteCarlo) playout(hand []string, missing string, unseen []string, draws int) bool {
+	hand = append([]string(nil), hand...)
+	wall := append([]string(nil), unseen...)
+	for i := 0; i < draws && i < len(wall); i++ {
+		j := i + m.rng.Intn(len(wall)-i)
+		wall[i], wall[j] = wall[j], wall[i]
+		hand = append(hand, wall[i])
+		if rules.CanWin(hand, missing) {
+			return true
+		}
+
+		discard, minShanten := "", 0
+		for _, tile := range discardCandidates(hand, missing) {
+			after, _ := rules.Remove(hand, tile, 1)
+			if s := rules.Shanten(after, missing); discard == "" || s < minShanten {
+				discard, minShanten = tile, s
+			}
+		}
+		hand, _ = rules.Remove(hand, discard, 1)
+	}
+	return false
+}
+
+// unseenTiles 自己看不到的牌：别人的手牌和牌墙
+func unseenTiles(v *View) []string {
+	tiles := make([]string, 0, rules.WallSize)
+	for i := 0; i < rules.TileKinds; i++ {
+		tile := rules.TileAt(i)
+		for n := v.Visible(tile); n < 4; n++ {
+			tiles = append(tiles, tile)
+		}
+	}
+	return tiles
+}
@@ -0,0 +1,228 @@
+package bot
+
+import (
+	"goMahjong/model"
+	"goMahjong/rules"
+	"sort"
+)
+
+// Strategy 机器人的决策，只能使用 View 中该座位看得到的信息
+type Strategy interface {
+	// Dingque 开局定缺的花色
+	Dingque(v *View) string
+	// Claim 别人出牌后从 v.Options 中选一个动作，不想要时返回 pass
+	Claim(v *View) string
+	// Turn 自己的回合出牌前是否自摸或杠，返回空字符串表示直接出牌，杠的时候同时返回要杠的牌
+	Turn(v *View) (action string, tile string)
+	// Discard 要打出的牌
+	Discard(v *View) string
+}
+
+// View 机器人视角的对局快照
+type View struct {
+	model.GameSnapshotData
+	PlayerID string
+}
+
+// Me 机器人自己的座位
+func (v *View) Me() *model.SeatInfo {
+	for i := range v.Players {
+		if v.Players[i].ID == v.PlayerID {
+			return &v.Players[i]
+		}
+	}
+	return &model.SeatInfo{}
+}
+
+// Missing 自己定缺的花色，还没定缺时为空
+func (v *View) Missing() string {
+	return v.Me().Missing
+}
+
+// Visible 某张牌已经看得到的张数：自己的手牌、所有人的弃牌和副露
+func (v *View) Visible(tile string) int {
+	n := rules.Count(v.Tiles, tile)
+	for _, seat := range v.Players {
+		n += rules.Count(seat.Discards, tile)
+		for _, meld := range seat.Melds {
+			if meld.Tile == tile {
+				n += meld.Size()
+			}
+		}
+	}
+	return n
+}
+
+// Greedy 贪心策略：打出之后向听数最小的牌，向听数相同时留下进张最多的；
+// 能胡就胡，碰杠不会让向听数变差时才碰杠
+type Greedy struct{}
+
+// Dingque 定缺手里最少的花色
+func (Greedy) Dingque(v *View) string {
+	return fewestSuit(v.Tiles)
+}
+
+// Claim 能胡就胡；杠之后向听数不变差就杠；碰之后向听数变小才碰
+func (Greedy) Claim(v *View) string {
+	missing := v.Missing()
+	current := rules.Shanten(v.Tiles, missing)
+	tile := v.LastPlayedTile
+	for _, action := range []string{model.ActionHu, model.ActionGang, model.ActionPeng} {
+		if !contains(v.Options, action) {
+			continue
+		}
+		switch action {
+		case model.ActionHu:
+			return action
+		case model.ActionGang:
+			if after, ok := rules.Remove(v.Tiles, tile, 3); ok && rules.Shanten(after, missing) <= current {
+				return action
+			}
+		case model.ActionPeng:
+			if after, ok := rules.Remove(v.Tiles, tile, 2); ok && bestShanten(after, missing) < current {
+				return action
+			}
+		}
+	}
+	return model.ActionPass
+}
+
+// Turn 能自摸就胡；杠之后向听数不比打一张牌差就杠
+func (Greedy) Turn(v *View) (string, string) {
+	if contains(v.Options, model.ActionHu) {
+		return model.ActionHu, ""
+	}
+	if contains(v.Options, model.ActionGang) {
+		missing := v.Missing()
+		current := bestShanten(v.Tiles, missing)
+		for _, tile := range gangTiles(v) {
+			n := rules.Count(v.Tiles, tile)
+			if after, ok := rules.Remove(v.Tiles, tile, n); ok && rules.Shanten(after, missing) <= current {
+				return model.ActionGang, tile
+			}
+		}
+	}
+	return "", ""
+}
+
+// Discard 打出向听数最小、进张最多的牌，缺门的牌先打
+func (Greedy) Discard(v *View) string {
+	return rankDiscards(v)[0].tile
+}
+
+// discardOption 打出一张牌之后的手牌评估
+type discardOption struct {
+	tile    string
+	shanten int
+	ukeire  int
+}
+
+// rankDiscards 按向听数从小到大、进张数从多到少排列可以打出的牌
+func rankDiscards(v *View) []discardOption {
+	missing := v.Missing()
+	options := make([]discardOption, 0, len(v.Tiles))
+	for _, tile := range discardCandidates(v.Tiles, missing) {
+		after, _ := rules.Remove(v.Tiles, tile, 1)
+		shanten := rules.Shanten(after, missing)
+		options = append(options, discardOption{tile: tile, shanten: shanten, ukeire: ukeire(v, after, shanten)})
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		if options[i].shanten != options[j].shanten {
+			return options[i].shanten < options[j].shanten
+		}
+		return options[i].ukeire > options[j].ukeire
+	})
+	return options
+}
+
+// fewestSuit 手里张数最少的花色
+func fewestSuit(tiles []string) string {
+	missing := rules.SuitTiao
+	for _, suit := range rules.Suits {
+		if rules.CountSuit(tiles, suit) < rules.CountSuit(tiles, missing) {
+			missing = suit
+		}
+	}
+	return missing
+}
+
+// discardCandidates 可以打出的牌，每种一张：手里有缺门的牌时只能打缺门
+func discardCandidates(hand []string, missing string) []string {
+	candidates := make([]string, 0, len(hand))
+	for _, tile := range hand {
+		if rules.Suit(tile) == missing && !contains(candidates, tile) {
+			candidates = append(candidates, tile)
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates
+	}
+	for _, tile := range hand {
+		if !contains(candidates, tile) {
+			candidates = append(candidates, tile)
+		}
+	}
+	return candidates
+}
+
+// bestShanten 打出一张牌之后能达到的最小向听数，hand 为 3n+2 张
+func bestShanten(hand []string, missing string) int {
+	best := len(hand)
+	for _, tile := range discardCandidates(hand, missing) {
+		after, _ := rules.Remove(hand, tile, 1)
+		if s := rules.Shanten(after, missing); s < best {
+			best = s
+		}
+	}
+	return best
+}
+
+// ukeire 进张数：还没看到的牌中，摸到之后能让向听数变小的张数
+func ukeire(v *View, hand []string, shanten int) int {
+	missing := v.Missing()
+	total := 0
+	for i := 0; i < rules.TileKinds; i++ {
+		tile := rules.TileAt(i)
+		if rules.Suit(tile) == missing {
+			continue
+		}
+		left := 4 - v.Visible(tile)
+		if left <= 0 {
+			continue
+		}
+		if rules.Shanten(append(hand[:len(hand):len(hand)], tile), missing) < shanten {
+			total += left
+		}
+	}
+	return total
+}
+
+// gangTiles 自己回合可以暗杠或补杠的牌
+func gangTiles(v *View) []string {
+	tiles := make([]string, 0)
+	missing := v.Missing()
+	for _, tile := range v.Tiles {
+		if rules.Suit(tile) == missing || contains(tiles, tile) {
+			continue
+		}
+		if rules.Count(v.Tiles, tile) == 4 {
+			tiles = append(tiles, tile)
+			continue
+		}
+		for _, meld := range v.Me().Melds {
+			if meld.Kind == rules.MeldPeng && meld.Tile == tile {
+				tiles = append(tiles, tile)
+			}
+		}
+	}
+	return tiles
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
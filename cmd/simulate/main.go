@@ -0,0 +1,53 @@
+// simulate 机器人自对弈，在进程内模拟大量对局并输出统计结果
+//
+//	go run ./cmd/simulate -hands 1000 -levels normal,normal,hard,expert
+//
+// 有违规的局时以状态码1退出
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"goMahjong/config"
+	"goMahjong/simulate"
+	"os"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func main() {
+	hands := flag.Int("hands", 1000, "模拟的局数")
+	seed := flag.Int64("seed", 1, "第一局的发牌种子，第 i 局用 seed+i")
+	levels := flag.String("levels", "normal,normal,normal,normal", "每个座位机器人的难度，逗号分隔，2到4个")
+	workers := flag.Int("workers", runtime.NumCPU(), "并行的协程数")
+	asJSON := flag.Bool("json", false, "以JSON格式输出")
+	flag.Parse()
+
+	// 对局过程中的日志太多，只保留警告和错误
+	config.SetLogLevel(zapcore.WarnLevel)
+
+	report, err := simulate.Run(simulate.Config{
+		Hands:   *hands,
+		Seed:    *seed,
+		Levels:  strings.Split(*levels, ","),
+		Workers: *workers,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		fmt.Print(report)
+	}
+	if len(report.Violations) > 0 {
+		os.Exit(1)
+	}
+}
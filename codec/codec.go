@@ -0,0 +1,31 @@
+package codec
+
+import "goMahjong/model"
+
+// 支持的WebSocket子协议
+const (
+	SubprotocolJSON     = "mahjong.json"
+	SubprotocolProtobuf = "mahjong.protobuf"
+)
+
+// codecs 按服务端的偏好顺序排列
+var codecs = []model.Codec{Protobuf{}, JSON{}}
+
+// Subprotocols 返回服务端支持的子协议，用于WebSocket握手
+func Subprotocols() []string {
+	names := make([]string, 0, len(codecs))
+	for _, c := range codecs {
+		names = append(names, c.Subprotocol())
+	}
+	return names
+}
+
+// ForSubprotocol 根据握手选中的子协议返回编码，客户端没有声明子协议时使用JSON
+func ForSubprotocol(name string) model.Codec {
+	for _, c := range codecs {
+		if c.Subprotocol() == name {
+			return c
+		}
+	}
+	return JSON{}
+}
@@ -0,0 +1,44 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"goMahjong/model"
+
+	"github.com/gorilla/websocket"
+)
+
+// JSON 文本编码，浏览器客户端使用
+type JSON struct{}
+
+// Subprotocol 子协议名
+func (JSON) Subprotocol() string {
+	return SubprotocolJSON
+}
+
+// FrameType 使用文本帧
+func (JSON) FrameType() int {
+	return websocket.TextMessage
+}
+
+// Encode 编码服务端消息
+func (JSON) Encode(message model.Message) ([]byte, error) {
+	return json.Marshal(message)
+}
+
+// Decode 解码客户端消息的外层
+func (JSON) Decode(data []byte) (model.Envelope, error) {
+	var envelope model.Envelope
+	err := json.Unmarshal(data, &envelope)
+	return envelope, err
+}
+
+// DecodePayload 解析请求负载，不认识的字段视为错误
+func (JSON) DecodePayload(data []byte, v interface{}) error {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,376 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"goMahjong/model"
+	"goMahjong/proto/mahjongpb"
+	"reflect"
+	"strconv"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Protobuf 二进制编码，线上格式与 proto/mahjong.proto 一致
+// 编解码由 protoc-gen-go 生成的 mahjongpb 类型和官方运行时完成，model 的结构体按名字对应到同名的proto消息，
+// 字段号来自结构体的pb标签，按下面的规则对应到proto类型：
+// string -> string，bool -> bool，int -> sint64，uint64 -> uint64，
+// 结构体和指针 -> 嵌套消息，切片 -> repeated，map -> map，
+// Message.Data 和 Envelope.Data -> bytes，内容是按消息类型对应的负载消息
+// 匿名嵌入的结构体字段直接展开到外层消息，与JSON的处理方式相同
+type Protobuf struct{}
+
+// Subprotocol 子协议名
+func (Protobuf) Subprotocol() string {
+	return SubprotocolProtobuf
+}
+
+// FrameType 使用二进制帧
+func (Protobuf) FrameType() int {
+	return websocket.BinaryMessage
+}
+
+// Encode 编码服务端消息
+func (Protobuf) Encode(message model.Message) ([]byte, error) {
+	return Marshal(message)
+}
+
+// Decode 解码客户端消息的外层
+func (Protobuf) Decode(data []byte) (model.Envelope, error) {
+	var envelope model.Envelope
+	err := Unmarshal(data, &envelope)
+	return envelope, err
+}
+
+// DecodePayload 解析请求负载
+func (Protobuf) DecodePayload(data []byte, v interface{}) error {
+	return Unmarshal(data, v)
+}
+
+// Marshal 把带pb标签的结构体转换成同名的生成类型后编码
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("protobuf: 不支持的类型 %s", rv.Type())
+	}
+	m, err := newMessage(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	if err := fromStruct(m, rv); err != nil {
+		return nil, err
+	}
+	// map按key排序保证编码结果稳定
+	return proto.MarshalOptions{Deterministic: true}.Marshal(m.Interface())
+}
+
+// Unmarshal 解码到同名的生成类型，再按pb标签复制到结构体指针
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("protobuf: 需要结构体指针")
+	}
+	m, err := newMessage(rv.Elem().Type())
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(data, m.Interface()); err != nil {
+		return err
+	}
+	if err := checkWireTypes(m); err != nil {
+		return err
+	}
+	return toStruct(m, rv.Elem())
+}
+
+// FieldNumber 返回结构体字段的pb字段号，没有标签时返回0
+func FieldNumber(field reflect.StructField) protowire.Number {
+	n, err := strconv.Atoi(field.Tag.Get("pb"))
+	if err != nil {
+		return 0
+	}
+	return protowire.Number(n)
+}
+
+// IsEmbedded 判断字段是否是需要展开的匿名结构体
+func IsEmbedded(field reflect.StructField) bool {
+	return field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("pb") == ""
+}
+
+// newMessage 创建结构体对应的生成类型的空消息
+func newMessage(t reflect.Type) (protoreflect.Message, error) {
+	name := mahjongpb.File_proto_mahjong_proto.Package().Append(protoreflect.Name(t.Name()))
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: %s 没有对应的proto消息: %w", t, err)
+	}
+	return mt.New(), nil
+}
+
+// fromStruct 按pb标签把结构体的字段写入消息
+func fromStruct(m protoreflect.Message, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		var err error
+		if IsEmbedded(field) {
+			err = fromStruct(m, v.Field(i))
+		} else if num := FieldNumber(field); num > 0 {
+			err = setField(m, num, v.Field(i))
+		}
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+	}
+	return nil
+}
+
+// setField 写入一个字段，零值和nil按proto3的规则不写
+func setField(m protoreflect.Message, num protowire.Number, v reflect.Value) error {
+	fd := m.Descriptor().Fields().ByNumber(num)
+	if fd == nil {
+		return fmt.Errorf("%s 没有字段 %d", m.Descriptor().FullName(), num)
+	}
+	switch {
+	case fd.IsMap():
+		if v.Kind() != reflect.Map {
+			return fmt.Errorf("%s 是map，不能对应 %s", fd.Name(), v.Type())
+		}
+		if v.Len() == 0 {
+			return nil
+		}
+		entries := m.Mutable(fd).Map()
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := toValue(fd.MapKey(), iter.Key(), nil)
+			if err != nil {
+				return err
+			}
+			value, err := toValue(fd.MapValue(), iter.Value(), entries.NewValue)
+			if err != nil {
+				return err
+			}
+			entries.Set(key.MapKey(), value)
+		}
+	case fd.IsList():
+		if v.Kind() != reflect.Slice {
+			return fmt.Errorf("%s 是repeated，不能对应 %s", fd.Name(), v.Type())
+		}
+		if v.Len() == 0 {
+			return nil
+		}
+		list := m.Mutable(fd).List()
+		for i := 0; i < v.Len(); i++ {
+			value, err := toValue(fd, v.Index(i), list.NewElement)
+			if err != nil {
+				return err
+			}
+			list.Append(value)
+		}
+	default:
+		if v.IsZero() {
+			return nil
+		}
+		value, err := toValue(fd, v, func() protoreflect.Value { return m.NewField(fd) })
+		if err != nil {
+			return err
+		}
+		m.Set(fd, value)
+	}
+	return nil
+}
+
+// toValue 把Go的值转换成字段的proto值，newValue 用于创建嵌套消息
+func toValue(fd protoreflect.FieldDescriptor, v reflect.Value, newValue func() protoreflect.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		if v.Kind() == reflect.String {
+			return protoreflect.ValueOfString(v.String()), nil
+		}
+	case protoreflect.BoolKind:
+		if v.Kind() == reflect.Bool {
+			return protoreflect.ValueOfBool(v.Bool()), nil
+		}
+	case protoreflect.Sint64Kind:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return protoreflect.ValueOfInt64(v.Int()), nil
+		}
+	case protoreflect.Uint64Kind:
+		switch v.Kind() {
+		case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return protoreflect.ValueOfUint64(v.Uint()), nil
+		}
+	case protoreflect.BytesKind:
+		// Message.Data 是负载结构体，编码成对应的负载消息
+		if v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return protoreflect.ValueOfBytes(nil), nil
+			}
+			data, err := Marshal(v.Interface())
+			return protoreflect.ValueOfBytes(data), err
+		}
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return protoreflect.ValueOfBytes(v.Bytes()), nil
+		}
+	case protoreflect.MessageKind:
+		v = reflect.Indirect(v)
+		if v.Kind() == reflect.Struct {
+			value := newValue()
+			return value, fromStruct(value.Message(), v)
+		}
+	}
+	return protoreflect.Value{}, fmt.Errorf("%s 是 %s，不能对应 %s", fd.Name(), fd.Kind(), v.Type())
+}
+
+// toStruct 按pb标签把消息的字段复制到结构体，消息中没有的字段保持零值
+func toStruct(m protoreflect.Message, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		var err error
+		if IsEmbedded(field) {
+			err = toStruct(m, v.Field(i))
+		} else if num := FieldNumber(field); num > 0 {
+			err = getField(m, num, v.Field(i))
+		}
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+	}
+	return nil
+}
+
+// getField 读出一个字段
+func getField(m protoreflect.Message, num protowire.Number, v reflect.Value) error {
+	fd := m.Descriptor().Fields().ByNumber(num)
+	if fd == nil {
+		return fmt.Errorf("%s 没有字段 %d", m.Descriptor().FullName(), num)
+	}
+	if !m.Has(fd) {
+		return nil
+	}
+	switch {
+	case fd.IsMap():
+		if v.Kind() != reflect.Map {
+			return fmt.Errorf("%s 是map，不能对应 %s", fd.Name(), v.Type())
+		}
+		v.Set(reflect.MakeMap(v.Type()))
+		var err error
+		m.Get(fd).Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+			k := reflect.New(v.Type().Key()).Elem()
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err = fromValue(fd.MapKey(), key.Value(), k); err == nil {
+				err = fromValue(fd.MapValue(), value, e)
+			}
+			v.SetMapIndex(k, e)
+			return err == nil
+		})
+		return err
+	case fd.IsList():
+		if v.Kind() != reflect.Slice {
+			return fmt.Errorf("%s 是repeated，不能对应 %s", fd.Name(), v.Type())
+		}
+		list := m.Get(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err := fromValue(fd, list.Get(i), e); err != nil {
+				return err
+			}
+			v.Set(reflect.Append(v, e))
+		}
+		return nil
+	}
+	return fromValue(fd, m.Get(fd), v)
+}
+
+// fromValue 把字段的proto值写入Go的值
+func fromValue(fd protoreflect.FieldDescriptor, value protoreflect.Value, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		if v.Kind() == reflect.String {
+			v.SetString(value.String())
+			return nil
+		}
+	case protoreflect.BoolKind:
+		if v.Kind() == reflect.Bool {
+			v.SetBool(value.Bool())
+			return nil
+		}
+	case protoreflect.Sint64Kind:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v.SetInt(value.Int())
+			return nil
+		}
+	case protoreflect.Uint64Kind:
+		switch v.Kind() {
+		case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v.SetUint(value.Uint())
+			return nil
+		}
+	case protoreflect.BytesKind:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(append([]byte(nil), value.Bytes()...))
+			return nil
+		}
+	case protoreflect.MessageKind:
+		if v.Kind() == reflect.Struct {
+			return toStruct(value.Message(), v)
+		}
+	}
+	return fmt.Errorf("%s 是 %s，不能对应 %s", fd.Name(), fd.Kind(), v.Type())
+}
+
+// checkWireTypes 官方运行时把线上类型与字段不符的值当作未知字段保留，这里按错误处理
+func checkWireTypes(m protoreflect.Message) error {
+	b := m.GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		if m.Descriptor().Fields().ByNumber(num) != nil {
+			return fmt.Errorf("%s 字段 %d: 线上类型 %d 与字段不符", m.Descriptor().Name(), num, typ)
+		}
+		// 不认识的字段直接跳过，便于协议向前兼容
+		b = b[n:]
+		if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				value.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					err = checkWireTypes(v.Message())
+					return err == nil
+				})
+			}
+		case fd.IsList():
+			if fd.Kind() == protoreflect.MessageKind {
+				for i := 0; i < value.List().Len() && err == nil; i++ {
+					err = checkWireTypes(value.List().Get(i).Message())
+				}
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			err = checkWireTypes(value.Message())
+		}
+		return err == nil
+	})
+	return err
+}
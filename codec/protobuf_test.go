@@ -0,0 +1,197 @@
+package codec
+
+import (
+	"goMahjong/model"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fill 用非零值填满结构体，保证每个字段都参与编码
+func fill(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("5w")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int64:
+		v.SetInt(-3)
+	case reflect.Uint64:
+		v.SetUint(42)
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		// 第二个元素保持零值，检查repeated里的零值不会丢失
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		fill(v.Index(0))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		value := reflect.New(v.Type().Elem()).Elem()
+		fill(key)
+		fill(value)
+		v.SetMapIndex(key, value)
+	}
+}
+
+func TestProtobufRoundTripsCatalogue(t *testing.T) {
+	for _, spec := range model.Catalogue {
+		t.Run(spec.Direction+"/"+spec.Type, func(t *testing.T) {
+			typ := reflect.TypeOf(spec.Payload)
+			want := reflect.New(typ)
+			fill(want.Elem())
+
+			data, err := Marshal(want.Interface())
+			require.NoError(t, err)
+			got := reflect.New(typ)
+			require.NoError(t, Unmarshal(data, got.Interface()))
+			assert.Equal(t, want.Interface(), got.Interface())
+		})
+	}
+}
+
+func TestProtobufCodecDecodesRequests(t *testing.T) {
+	payload, err := Marshal(model.PlayTileRequest{Tile: "3t"})
+	require.NoError(t, err)
+	frame, err := Marshal(model.Envelope{Type: model.ReqPlayTile, Version: 1, Seq: 7, Data: payload})
+	require.NoError(t, err)
+
+	c := ForSubprotocol(SubprotocolProtobuf)
+	envelope, err := c.Decode(frame)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), envelope.Seq)
+
+	req, err := model.DecodeRequest(envelope, 1, c)
+	require.NoError(t, err)
+	assert.Equal(t, &model.PlayTileRequest{Tile: "3t"}, req)
+
+	// 负载里的非法牌同样会被校验出来
+	payload, _ = Marshal(model.PlayTileRequest{Tile: "0x"})
+	_, err = model.DecodeRequest(model.Envelope{Type: model.ReqPlayTile, Data: payload}, 1, c)
+	assert.Error(t, err)
+}
+
+func TestProtobufRejectsWrongWireType(t *testing.T) {
+	// 字段1按varint写入，但PlayTileRequest.Tile是string
+	var req model.PlayTileRequest
+	assert.Error(t, Unmarshal([]byte{0x08, 0x01}, &req))
+}
+
+func TestUnknownSubprotocolFallsBackToJSON(t *testing.T) {
+	assert.Equal(t, SubprotocolJSON, ForSubprotocol("").Subprotocol())
+	assert.Equal(t, SubprotocolJSON, ForSubprotocol("mahjong.xml").Subprotocol())
+}
+
+// protoType 按codec的映射规则给出Go类型对应的proto类型
+func protoType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int64:
+		return "sint64"
+	case reflect.Uint64:
+		return "uint64"
+	case reflect.Ptr:
+		return protoType(t.Elem())
+	case reflect.Interface:
+		return "bytes"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytes"
+		}
+		return "repeated " + protoType(t.Elem())
+	case reflect.Map:
+		return "map<" + protoType(t.Key()) + ", " + protoType(t.Elem()) + ">"
+	}
+	return t.Name()
+}
+
+// expectedFields 返回Go结构体应有的proto字段：字段号 -> 类型，并收集嵌套的消息类型
+func expectedFields(t reflect.Type, fields map[int]string, nested map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if IsEmbedded(field) {
+			expectedFields(field.Type, fields, nested)
+			continue
+		}
+		num := FieldNumber(field)
+		if num == 0 {
+			continue
+		}
+		fields[int(num)] = protoType(field.Type)
+
+		inner := field.Type
+		for inner.Kind() == reflect.Ptr || inner.Kind() == reflect.Slice {
+			inner = inner.Elem()
+		}
+		if inner.Kind() == reflect.Struct {
+			nested[inner.Name()] = inner
+		}
+	}
+}
+
+// parseProto 解析 .proto 文件中每个message的字段
+func parseProto(t *testing.T) map[string]map[int]string {
+	raw, err := os.ReadFile("../proto/mahjong.proto")
+	require.NoError(t, err)
+
+	messageRe := regexp.MustCompile(`(?s)message (\w+) \{(.*?)\}`)
+	fieldRe := regexp.MustCompile(`(?m)^\s*((?:repeated )?[\w<>, ]+?) \w+ = (\d+);`)
+	messages := make(map[string]map[int]string)
+	for _, m := range messageRe.FindAllStringSubmatch(string(raw), -1) {
+		fields := make(map[int]string)
+		for _, f := range fieldRe.FindAllStringSubmatch(m[2], -1) {
+			num, _ := strconv.Atoi(f[2])
+			fields[num] = strings.TrimSpace(f[1])
+		}
+		messages[m[1]] = fields
+	}
+	return messages
+}
+
+func TestProtoFileMatchesModel(t *testing.T) {
+	messages := parseProto(t)
+
+	types := map[string]reflect.Type{
+		"Message":  reflect.TypeOf(model.Message{}),
+		"Envelope": reflect.TypeOf(model.Envelope{}),
+	}
+	for _, spec := range model.Catalogue {
+		typ := reflect.TypeOf(spec.Payload)
+		types[typ.Name()] = typ
+	}
+
+	checked := make(map[string]bool)
+	for len(types) > 0 {
+		nested := make(map[string]reflect.Type)
+		for name, typ := range types {
+			if checked[name] {
+				continue
+			}
+			checked[name] = true
+
+			want := make(map[int]string)
+			expectedFields(typ, want, nested)
+			got, ok := messages[name]
+			if assert.True(t, ok, ".proto 缺少消息 %s", name) {
+				assert.Equal(t, want, got, "消息 %s 的字段与 model 不一致", name)
+			}
+		}
+		types = nested
+	}
+}
@@ -17,6 +17,7 @@ const (
 var (
 	zap_singleton *zap.Logger
 	zap_once      sync.Once
+	zap_level     = zap.NewAtomicLevelAt(zapcore.DebugLevel) // 最低日志级别，可以在运行时调整
 )
 
 func GetEnv() string {
@@ -27,7 +28,7 @@ func GetZapLogger() *zap.Logger {
 	zap_once.Do(func() {
 		config := zap.NewDevelopmentConfig()
 		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder // 彩色日志级别
-		config.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)             // 设置最低日志级别
+		config.Level = zap_level                                            // 设置最低日志级别
 		config.DisableStacktrace = true                                     // 禁用堆栈追踪
 		config.Encoding = "console"                                         // 控制台输出
 		config.OutputPaths = []string{"stdout"}                             // 确保输出到控制台
@@ -41,6 +42,11 @@ func GetZapLogger() *zap.Logger {
 	return zap_singleton
 }
 
+// SetLogLevel 调整最低日志级别，命令行工具用它关闭对局过程中的日志
+func SetLogLevel(level zapcore.Level) {
+	zap_level.SetLevel(level)
+}
+
 func Init() {
 
 	env := "dev"
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// 在读取配置文件之前注册默认值，未配置的项使用这里的值
+func init() {
+	setDefaults()
+}
+
+func setDefaults() {
+	// WebSocket连接
+	viper.SetDefault("websocket.sendQueueSize", 256)         // 每个连接的发送队列长度
+	viper.SetDefault("websocket.writeWait", 10*time.Second)  // 单条消息的写超时
+	viper.SetDefault("websocket.pongWait", 60*time.Second)   // 超过该时间没有收到任何数据即认为连接已断开
+	viper.SetDefault("websocket.pingPeriod", 25*time.Second) // ping间隔，必须小于pongWait
+	viper.SetDefault("websocket.maxMessageSize", 4096)       // 客户端单条消息的最大字节数
+
+	// 安全
+	viper.SetDefault("security.sessionSecret", "")        // 会话令牌签名密钥，为空时使用存储中保存的随机密钥
+	viper.SetDefault("security.sessionTTL", 24*time.Hour) // 会话令牌有效期
+	viper.SetDefault("security.joinMaxFailures", 10)      // 每个IP在窗口期内加入同一房间的最大失败次数
+	viper.SetDefault("security.joinMaxFailuresPerIP", 30) // 每个IP在窗口期内加入房间的最大总失败次数，成功加入也不清空
+	viper.SetDefault("security.joinFailureWindow", 10*time.Minute)
+	viper.SetDefault("security.trustedProxies", []string{}) // 信任的反向代理地址或网段，只有经过它们的请求才按 X-Forwarded-For 取客户端IP，为空表示不信任任何代理
+
+	// 游戏
+	viper.SetDefault("game.reconnectGrace", 2*time.Minute)  // 对局中掉线后保留座位的时间
+	viper.SetDefault("game.messageHistorySize", 512)        // 每个房间保留的广播记录条数，用于断档补发
+	viper.SetDefault("game.baseScore", 1)                   // 底分
+	viper.SetDefault("game.maxFan", 4)                      // 封顶番数
+	viper.SetDefault("game.dingqueTimeout", 20*time.Second) // 定缺的时间，超时定缺手里最少的花色，0 表示不计时
+	viper.SetDefault("game.turnTimeout", 15*time.Second)    // 出牌的基础时间，超时先用时间银行，再自动出牌
+	viper.SetDefault("game.claimTimeout", 8*time.Second)    // 响应碰杠胡的时间，超时自动过
+	viper.SetDefault("game.timeBank", 30*time.Second)       // 每局的时间银行，出牌超出基础时间时使用
+	viper.SetDefault("game.maxSpectators", 8)               // 每个房间最多的观战人数
+	viper.SetDefault("game.coachDelay", 30*time.Second)     // 教练视角延迟公开手牌的时间，0 表示不允许教练观战
+	viper.SetDefault("game.coachKey", "")                   // 教练观战需要的密钥，为空表示不允许教练观战
+
+	// 房间
+	viper.SetDefault("room.maxRooms", 1000)                  // 房间总数上限，0 表示不限制
+	viper.SetDefault("room.maxRoomsPerIP", 5)                // 每个IP同时创建的房间数上限，0 表示不限制
+	viper.SetDefault("room.idleTimeout", 10*time.Minute)     // 没有人在线超过该时间的房间被关闭，0 表示不关闭
+	viper.SetDefault("room.finishedTimeout", 30*time.Minute) // 打完之后超过该时间没有再开局的房间被关闭，0 表示不关闭
+	viper.SetDefault("room.janitorInterval", 30*time.Second) // 检查房间的间隔
+
+	// 存储
+	viper.SetDefault("store.driver", "bolt")                     // memory 只保存在内存中；bolt 保存在本地数据库文件中
+	viper.SetDefault("store.path", "data/mahjong.db")            // bolt 数据库文件路径
+	viper.SetDefault("store.checkpointInterval", 10*time.Second) // 保存进行中房间的间隔
+
+	// 回放
+	viper.SetDefault("replay.maxImportSize", 1<<20) // 导入牌谱的最大字节数
+
+	// 机器人
+	viper.SetDefault("bot.thinkTime", 800*time.Millisecond) // 机器人每次行动前的停顿
+	viper.SetDefault("bot.autoPlayLevel", "normal")         // 托管时代替玩家的机器人难度
+
+	// 停服
+	viper.SetDefault("server.shutdownCountdown", 30*time.Second) // 通知玩家后等待对局结束的最长时间
+	viper.SetDefault("server.shutdownTimeout", 45*time.Second)   // 停服的总时限，必须大于shutdownCountdown
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"goMahjong/bot"
+	"goMahjong/config"
+	"goMahjong/model"
+	"goMahjong/service"
+	"goMahjong/transport"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// botTable 机器人所在的牌桌，机器人的请求和客户端的请求走同一条处理流程
+type botTable struct {
+	player      *model.Player
+	room        *model.Room
+	gameManager *service.GameManager
+	bot         *bot.Bot // 坐在这里的机器人，创建机器人之后在持有房间锁时设置
+}
+
+// Snapshot 加锁获取机器人视角的快照，视图不和房间共享切片，可以在锁外使用
+func (t *botTable) Snapshot() (model.GameSnapshotData, uint64, bool) {
+	t.room.Lock()
+	defer t.room.Unlock()
+
+	if t.room.GameState != model.GameStatePlaying || t.room.GetPlayer(t.player.ID) == nil {
+		return model.GameSnapshotData{}, 0, false
+	}
+	return t.room.SeatView(t.player.ID), t.room.Seq(), true
+}
+
+// Submit 按客户端消息的处理流程处理机器人的请求
+// 被拒绝的请求只记录日志，不发给玩家：托管时玩家的连接不应该收到机器人请求的错误
+// 机器人在锁外做决定，提交时可能已经取消托管或玩家已经重连，这时它已不在座位上登记，请求直接丢弃
+func (t *botTable) Submit(msgType string, req interface{}, seq uint64) {
+	t.room.Lock()
+	defer t.room.Unlock()
+
+	if !transport.For(t.room).Serves(t.player.ID, t.bot) {
+		return
+	}
+
+	if _, err := handlePlayerMessageSafely(t.player, t.room, t.gameManager, model.Envelope{Type: msgType, Seq: seq}, req); err != nil {
+		config.GetZapLogger().Warn("机器人 " + t.player.Name + " 的请求 " + msgType + " 被拒绝: " + err.Error())
+	}
+}
+
+// addBot 房主添加指定难度的机器人，调用方需持有房间锁
+func addBot(owner *model.Player, room *model.Room, gameManager *service.GameManager, level string) error {
+	if !room.IsOwner(owner.ID) {
+		return model.NewGameError(model.ErrCodeForbidden, "只有房主可以添加机器人")
+	}
+	if room.GameState == model.GameStatePlaying {
+		return model.NewGameError(model.ErrCodeInvalidState, "对局进行中不能添加机器人")
+	}
+	if len(room.Players) >= model.MaxPlayers {
+		return model.NewGameError(model.ErrCodeInvalidState, "房间已满")
+	}
+	if level == "" {
+		level = bot.LevelNormal
+	}
+	if _, err := bot.NewStrategy(level, 0); err != nil {
+		return model.NewGameError(model.ErrCodeBadRequest, "未知的机器人难度: "+level)
+	}
+
+	player := model.NewPlayer(botName(room))
+	player.Bot = true
+	player.BotLevel = level
+	room.AddPlayer(player)
+	attachBot(player, room, gameManager)
+	config.GetZapLogger().Info("房间 " + room.ID + " 添加了机器人 " + player.Name + "，难度 " + level)
+
+	room.BroadcastAll(model.Message{
+		Type: model.MsgPlayerJoined,
+		Data: model.PlayerJoinedData{Player: player.GetPublicInfo()},
+	})
+	gameManager.SaveRoom(room)
+	return nil
+}
+
+// attachBot 按玩家的难度让机器人接管座位，调用方需持有房间锁
+func attachBot(player *model.Player, room *model.Room, gameManager *service.GameManager) {
+	strategy, err := bot.NewStrategy(player.BotLevel, time.Now().UnixNano())
+	if err != nil {
+		config.GetZapLogger().Warn("机器人 " + player.Name + " 的难度 " + player.BotLevel + " 无效，改用 " + bot.LevelNormal)
+		strategy = bot.Greedy{}
+	}
+	transport.For(room).Attach(player.ID, newBot(player, room, gameManager, strategy))
+	player.Connected = true
+}
+
+// setAutoPlay 开始或取消托管并通知所有人，调用方需持有房间锁
+// reason 为开始托管的原因：disconnected 或 manual
+func setAutoPlay(player *model.Player, room *model.Room, gameManager *service.GameManager, enabled bool, reason string) {
+	if player.Bot || player.AutoPlay == enabled {
+		return
+	}
+	logger := config.GetZapLogger()
+
+	if enabled {
+		level := viper.GetString("bot.autoPlayLevel")
+		strategy, err := bot.NewStrategy(level, time.Now().UnixNano())
+		if err != nil {
+			logger.Warn("托管难度 " + level + " 无效，改用 " + bot.LevelNormal)
+			strategy = bot.Greedy{}
+		}
+		transport.For(room).StartAutoPlay(player.ID, newBot(player, room, gameManager, strategy))
+		logger.Info("玩家 " + player.Name + " 开始托管，原因: " + reason)
+	} else {
+		transport.For(room).StopAutoPlay(player.ID)
+		reason = ""
+		logger.Info("玩家 " + player.Name + " 取消托管")
+	}
+	player.AutoPlay = enabled
+
+	// 托管的机器人也会收到这条消息，随即开始行动；取消托管后机器人不再登记，
+	// 它停止前已经算好的请求会在 Submit 中被丢弃
+	room.BroadcastAll(model.Message{
+		Type: model.MsgAutoPlay,
+		Data: model.AutoPlayData{PlayerID: player.ID, Enabled: enabled, Reason: reason},
+	})
+}
+
+// newBot 创建坐在玩家座位上的机器人，调用方需持有房间锁，并负责把机器人登记为玩家的连接或托管
+func newBot(player *model.Player, room *model.Room, gameManager *service.GameManager, strategy bot.Strategy) *bot.Bot {
+	table := &botTable{player: player, room: room, gameManager: gameManager}
+	table.bot = bot.New(player.ID, strategy, table)
+	return table.bot
+}
+
+// botName 房间中还没用过的机器人名字
+func botName(room *model.Room) string {
+	for i := 1; ; i++ {
+		name := "机器人" + strconv.Itoa(i)
+		used := false
+		for _, p := range room.Players {
+			if p.Name == name {
+				used = true
+			}
+		}
+		if !used {
+			return name
+		}
+	}
+}
@@ -0,0 +1,1108 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"goMahjong/bot"
+	"goMahjong/codec"
+	"goMahjong/model"
+	"goMahjong/rules"
+	"goMahjong/service"
+	"goMahjong/store"
+	"goMahjong/transport"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer 按 api.Init 的方式注册API和WebSocket路由（不加载页面模板）
+func newTestServer(t *testing.T) *httptest.Server {
+	return newTestServerWith(t, service.NewGameManager(store.NewMemory()))
+}
+
+func newTestServerWith(t *testing.T, gameManager *service.GameManager) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	_ = engine.SetTrustedProxies(nil)
+	joinLimiter := service.NewAttemptLimiter(10, 30, time.Minute)
+
+	engine.GET("/ws/:roomID", func(c *gin.Context) {
+		HandleWebSocket(c, gameManager)
+	})
+	engine.GET("/api/protocol", ProtocolHandler)
+	engine.GET("/api/replays/:replayID", ReplayAPIHandler(gameManager))
+	engine.GET("/api/replays/:replayID/mjai", ExportMJAIHandler(gameManager))
+	engine.POST("/api/replays/import", ImportMJAIHandler(gameManager))
+	engine.POST("/api/room/create", CreateRoomAPIHandler(gameManager))
+	engine.POST("/api/room/join", JoinRoomAPIHandler(gameManager, joinLimiter))
+	engine.POST("/api/room/spectate", SpectateRoomAPIHandler(gameManager, joinLimiter))
+
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	// 先关闭测试留下的房间，停止托管的机器人，不让它们在之后的测试中继续读取配置
+	t.Cleanup(func() {
+		for _, room := range gameManager.GetAllRooms() {
+			room.Lock()
+			gameManager.CloseRoom(room, "测试结束")
+			room.Unlock()
+		}
+	})
+	return server
+}
+
+// roomCredentials 创建/加入房间接口的返回
+type roomCredentials struct {
+	RoomID   string `json:"roomID"`
+	PlayerID string `json:"playerID"`
+	Token    string `json:"token"`
+}
+
+func postJSON(t *testing.T, url string, body interface{}) roomCredentials {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+	resp, err := http.Post(url, "application/json", bytes.NewReader(payload))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var creds roomCredentials
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&creds))
+	return creds
+}
+
+// scriptedClient 按脚本收发消息的测试客户端，收到的每条消息都会对照协议目录校验
+type scriptedClient struct {
+	t        *testing.T
+	conn     *websocket.Conn
+	playerID string
+	lastSeq  uint64   // 收到的最大序号
+	hand     []string // 开局时收到的手牌
+	missing  string   // 定缺的花色
+	received []string // 收到的消息类型，按顺序
+}
+
+func dialClient(t *testing.T, server *httptest.Server, creds roomCredentials) *scriptedClient {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + creds.RoomID + "?v=1"
+	dialer := websocket.Dialer{Subprotocols: []string{tokenSubprotocolPrefix + creds.Token}}
+	conn, _, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return &scriptedClient{t: t, conn: conn, playerID: creds.PlayerID}
+}
+
+func (c *scriptedClient) send(msgType string, data interface{}) {
+	c.sendWithSeq(msgType, data, c.lastSeq)
+}
+
+func (c *scriptedClient) sendWithSeq(msgType string, data interface{}, seq uint64) {
+	require.NoError(c.t, c.conn.WriteJSON(map[string]interface{}{"type": msgType, "v": 1, "seq": seq, "data": data}))
+}
+
+// expect 读取消息直到收到指定类型，途中的其他消息同样要符合协议
+func (c *scriptedClient) expect(msgType string) map[string]interface{} {
+	c.t.Helper()
+	for {
+		received, result := c.read()
+		if received == msgType {
+			return result
+		}
+	}
+}
+
+// read 读取下一条消息并对照协议目录校验
+func (c *scriptedClient) read() (string, map[string]interface{}) {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, raw, err := c.conn.ReadMessage()
+	require.NoError(c.t, err, "读取消息失败")
+
+	var message struct {
+		Type    string          `json:"type"`
+		Version int             `json:"v"`
+		Seq     uint64          `json:"seq"`
+		Data    json.RawMessage `json:"data"`
+	}
+	require.NoError(c.t, json.Unmarshal(raw, &message))
+	if message.Seq > c.lastSeq {
+		c.lastSeq = message.Seq
+	}
+
+	spec, ok := model.FindMessageSpec(model.DirectionServer, message.Type)
+	require.True(c.t, ok, "服务端发出了目录中没有的消息类型 %q", message.Type)
+	assert.Equal(c.t, model.ProtocolVersion, message.Version)
+
+	var data interface{}
+	require.NoError(c.t, json.Unmarshal(message.Data, &data))
+	schema := model.JSONSchema(reflect.TypeOf(spec.Payload))
+	require.NoError(c.t, validateSchema(schema, data, message.Type), string(raw))
+
+	result, _ := data.(map[string]interface{})
+	if message.Type == model.MsgGameStarted {
+		c.hand = c.hand[:0]
+		for _, tile := range result["tiles"].([]interface{}) {
+			c.hand = append(c.hand, tile.(string))
+		}
+	}
+	c.received = append(c.received, message.Type)
+	return message.Type, result
+}
+
+// declareMissing 定缺手里最少的花色
+func (c *scriptedClient) declareMissing() {
+	c.missing = rules.SuitTiao
+	for _, suit := range rules.Suits {
+		if rules.CountSuit(c.hand, suit) < rules.CountSuit(c.hand, c.missing) {
+			c.missing = suit
+		}
+	}
+	c.send(model.ReqDingque, map[string]interface{}{"suit": c.missing})
+}
+
+// discardChoices 按规则可以打出的牌：缺门的牌要先打完
+func (c *scriptedClient) discardChoices() []string {
+	choices := make([]string, 0)
+	for _, tile := range c.hand {
+		if rules.Suit(tile) == c.missing {
+			choices = append(choices, tile)
+		}
+	}
+	if len(choices) == 0 {
+		return c.hand
+	}
+	return choices
+}
+
+// startHand 房主开始游戏，所有人定缺，返回庄家ID
+func startHand(t *testing.T, owner *scriptedClient, clients map[string]*scriptedClient) string {
+	owner.send(model.ReqStartGame, map[string]interface{}{})
+	var dealerID string
+	for _, client := range clients {
+		started := client.expect(model.MsgGameStarted)
+		assert.Equal(t, string(model.PhaseDingque), started["phase"])
+		dealerID = started["dealerID"].(string)
+		assert.Equal(t, dealerID, started["currentPlayerID"])
+	}
+	for _, client := range clients {
+		client.declareMissing()
+	}
+	for id, client := range clients {
+		suits := client.expect(model.MsgDingque)["suits"].(map[string]interface{})
+		assert.Equal(t, client.missing, suits[id])
+		assert.Equal(t, dealerID, client.expect(model.MsgTurnChanged)["playerID"])
+	}
+	return dealerID
+}
+
+// passClaims 出牌之后，能碰杠胡的玩家都选择过，直到有人摸牌
+// 只有能碰杠胡的玩家收到 claim_window，其他人要等他们都选择之后才收到下一条消息，所以先处理能碰杠胡的玩家
+func passClaims(t *testing.T, clients map[string]*scriptedClient, fromID, tile string) {
+	for id, client := range clients {
+		if id == fromID || !client.canClaim(tile) {
+			continue
+		}
+		prompt := client.expect(model.MsgClaimWindow)
+		assert.Equal(t, tile, prompt["tile"])
+		client.send(model.ReqAction, map[string]interface{}{"action": model.ActionPass, "tiles": []string{}})
+	}
+	for _, client := range clients {
+		client.expect(model.MsgNewTile)
+	}
+}
+
+// canClaim 按开局时的手牌判断能不能碰杠胡别人打出的这张牌
+func (c *scriptedClient) canClaim(tile string) bool {
+	if rules.Suit(tile) == c.missing {
+		return false
+	}
+	return rules.Count(c.hand, tile) >= 2 || rules.CanWin(append(append([]string(nil), c.hand...), tile), c.missing)
+}
+
+func TestScriptedGameFollowsCatalogue(t *testing.T) {
+	server := newTestServer(t)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "玩家", "roomID": owner.RoomID})
+
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgWelcome)
+	ownerClient.expect(model.MsgRoomInfo)
+
+	guestClient := dialClient(t, server, guest)
+	guestClient.expect(model.MsgWelcome)
+	guestClient.expect(model.MsgRoomInfo)
+	ownerClient.expect(model.MsgPlayerJoined)
+
+	// 非房主不能开始游戏
+	guestClient.send(model.ReqStartGame, map[string]interface{}{})
+	errData := guestClient.expect(model.MsgError)
+	assert.Equal(t, model.ErrCodeForbidden, errData["code"])
+
+	// 格式错误和未知类型的消息返回结构化错误，连接不会断开
+	guestClient.send(model.ReqPlayTile, map[string]interface{}{"tile": 5})
+	assert.Equal(t, model.ErrCodeBadRequest, guestClient.expect(model.MsgError)["code"])
+	guestClient.send("no_such_message", nil)
+	assert.Equal(t, model.ErrCodeUnknownType, guestClient.expect(model.MsgError)["code"])
+
+	// 房主开始游戏，定缺之前不能出牌
+	clients := map[string]*scriptedClient{owner.PlayerID: ownerClient, guest.PlayerID: guestClient}
+	ownerClient.send(model.ReqStartGame, map[string]interface{}{})
+	var current *scriptedClient
+	for _, client := range clients {
+		started := client.expect(model.MsgGameStarted)
+		current = clients[started["dealerID"].(string)]
+	}
+	require.Len(t, current.hand, 14, "庄家起手14张")
+	current.send(model.ReqPlayTile, map[string]interface{}{"tile": current.hand[0]})
+	assert.Equal(t, model.ErrCodeInvalidState, current.expect(model.MsgError)["code"])
+
+	for _, client := range clients {
+		client.declareMissing()
+	}
+	for _, client := range clients {
+		client.expect(model.MsgDingque)
+		client.expect(model.MsgTurnChanged)
+	}
+
+	// 庄家出一张牌，所有人都收到出牌、摸牌和轮转消息
+	tile := current.discardChoices()[0]
+	current.send(model.ReqPlayTile, map[string]interface{}{"tile": tile})
+	for _, client := range clients {
+		played := client.expect(model.MsgTilePlayed)
+		assert.Equal(t, tile, played["tile"])
+	}
+	passClaims(t, clients, current.playerID, tile)
+	for _, client := range clients {
+		client.expect(model.MsgTurnChanged)
+	}
+
+	// 已经不是他的回合了
+	current.send(model.ReqPlayTile, map[string]interface{}{"tile": current.hand[1]})
+	assert.Equal(t, model.ErrCodeNotYourTurn, current.expect(model.MsgError)["code"])
+}
+
+func TestSequenceReplayAndStaleActions(t *testing.T) {
+	server := newTestServer(t)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "玩家", "roomID": owner.RoomID})
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgRoomInfo)
+	guestClient := dialClient(t, server, guest)
+	guestClient.expect(model.MsgRoomInfo)
+
+	clients := map[string]*scriptedClient{owner.PlayerID: ownerClient, guest.PlayerID: guestClient}
+	current := clients[startHand(t, ownerClient, clients)]
+	startSeq := guestClient.lastSeq
+
+	// 庄家出牌后轮转，每条广播占一个序号
+	guestClient.received = guestClient.received[:0]
+	tile := current.discardChoices()[0]
+	current.send(model.ReqPlayTile, map[string]interface{}{"tile": tile})
+	for _, client := range clients {
+		client.expect(model.MsgTilePlayed)
+	}
+	passClaims(t, clients, current.playerID, tile)
+	for _, client := range clients {
+		client.expect(model.MsgTurnChanged)
+	}
+	// 碰杠胡提示只发给能操作的玩家，不是广播，不占序号，选择之后也不再补发
+	original := make([]string, 0)
+	for _, msgType := range guestClient.received {
+		if msgType != model.MsgClaimWindow {
+			original = append(original, msgType)
+		}
+	}
+	endSeq := guestClient.lastSeq
+	assert.Equal(t, endSeq-startSeq, uint64(len(original)), "每条广播占一个序号")
+
+	// 请求补发开局之后的消息，收到的序号与原消息一致
+	guestClient.received = guestClient.received[:0]
+	guestClient.sendWithSeq(model.ReqResync, map[string]interface{}{"afterSeq": startSeq}, 0)
+	for len(guestClient.received) < len(original) {
+		guestClient.read()
+	}
+	assert.Equal(t, original, guestClient.received)
+	assert.Equal(t, endSeq, guestClient.lastSeq)
+
+	// 基于开局时序号的出牌请求已经过期
+	for _, c := range clients {
+		c.sendWithSeq(model.ReqPlayTile, map[string]interface{}{"tile": c.hand[1]}, startSeq)
+		assert.Equal(t, model.ErrCodeStaleAction, c.expect(model.MsgError)["code"])
+	}
+	// 不带序号的请求不能绕过检查
+	for _, c := range clients {
+		c.sendWithSeq(model.ReqPlayTile, map[string]interface{}{"tile": c.hand[1]}, 0)
+		assert.Equal(t, model.ErrCodeBadRequest, c.expect(model.MsgError)["code"])
+	}
+}
+
+func TestReconnectIntoRestoredRoom(t *testing.T) {
+	// 恢复的对局由机器人托管，让它在玩家重连之前不要行动
+	viper.Set("bot.thinkTime", time.Minute)
+	defer viper.Set("bot.thinkTime", nil)
+	st := store.NewMemory()
+	before := service.NewGameManager(st)
+	server := newTestServerWith(t, before)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "玩家", "roomID": owner.RoomID})
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgRoomInfo)
+	guestClient := dialClient(t, server, guest)
+	guestClient.expect(model.MsgRoomInfo)
+	clients := map[string]*scriptedClient{owner.PlayerID: ownerClient, guest.PlayerID: guestClient}
+	dealerID := startHand(t, ownerClient, clients)
+
+	// 保存检查点后服务停止
+	before.Checkpoint()
+	server.Close()
+
+	after := service.NewGameManager(st)
+	restored := after.RestoreRooms()
+	require.Len(t, restored, 1)
+	HoldRestoredSeats(restored[0], after)
+	server = newTestServerWith(t, after)
+
+	// 用原来的令牌重新连接，回到原来的座位和手牌
+	resumed := make(map[string]*scriptedClient)
+	for id, creds := range map[string]roomCredentials{owner.PlayerID: owner, guest.PlayerID: guest} {
+		client := dialClient(t, server, creds)
+		snapshot := client.expect(model.MsgGameSnapshot)
+		assert.Equal(t, dealerID, snapshot["currentPlayerID"])
+		assert.Equal(t, string(model.PhaseDiscard), snapshot["phase"])
+		tiles := make([]string, 0)
+		for _, tile := range snapshot["tiles"].([]interface{}) {
+			tiles = append(tiles, tile.(string))
+		}
+		assert.ElementsMatch(t, clients[id].hand, tiles)
+		assert.Greater(t, client.lastSeq, clients[id].lastSeq, "恢复后的序号要跳过服务停止前发出的消息")
+
+		client.hand, client.missing = clients[id].hand, clients[id].missing
+		resumed[id] = client
+	}
+
+	// 对局可以继续
+	current := resumed[dealerID]
+	tile := current.discardChoices()[0]
+	current.send(model.ReqPlayTile, map[string]interface{}{"tile": tile})
+	for _, client := range resumed {
+		client.expect(model.MsgTilePlayed)
+	}
+	passClaims(t, resumed, current.playerID, tile)
+	for _, client := range resumed {
+		client.expect(model.MsgTurnChanged)
+	}
+}
+
+func TestDrainRoomsNotifiesAndClosesConnections(t *testing.T) {
+	st := store.NewMemory()
+	gameManager := service.NewGameManager(st)
+	server := newTestServerWith(t, gameManager)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "玩家", "roomID": owner.RoomID})
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgRoomInfo)
+	guestClient := dialClient(t, server, guest)
+	guestClient.expect(model.MsgRoomInfo)
+	clients := map[string]*scriptedClient{owner.PlayerID: ownerClient, guest.PlayerID: guestClient}
+	startHand(t, ownerClient, clients)
+
+	drained := make(chan struct{})
+	go func() {
+		DrainRooms(context.Background(), gameManager, 300*time.Millisecond)
+		close(drained)
+	}()
+	for _, client := range clients {
+		data := client.expect(model.MsgServerShutdown)
+		assert.EqualValues(t, 1, data["seconds"])
+	}
+
+	// 停服期间不能再创建房间
+	body, _ := json.Marshal(map[string]string{"playerName": "新玩家"})
+	resp, err := http.Post(server.URL+"/api/room/create", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	// 倒计时结束后对局还没打完，保存检查点后用关闭帧断开
+	select {
+	case <-drained:
+	case <-time.After(2 * time.Second):
+		t.Fatal("停服没有结束")
+	}
+	for _, client := range clients {
+		var err error
+		for err == nil {
+			_, _, err = client.conn.ReadMessage()
+		}
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err.Error())
+	}
+
+	// 玩家的座位保留在存储中，下次启动时恢复
+	record, err := st.GetRoom(owner.RoomID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{owner.PlayerID, guest.PlayerID}, record.PlayerIDs)
+	assert.NotEmpty(t, record.Hand)
+}
+
+func TestOwnerAddsBotsThatPlayThroughTheRoom(t *testing.T) {
+	viper.Set("bot.thinkTime", time.Duration(0))
+	defer viper.Set("bot.thinkTime", nil)
+	gm := service.NewGameManager(store.NewMemory())
+	server := newTestServerWith(t, gm)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "玩家", "roomID": owner.RoomID})
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgRoomInfo)
+	guestClient := dialClient(t, server, guest)
+	guestClient.expect(model.MsgRoomInfo)
+	ownerClient.expect(model.MsgPlayerJoined)
+
+	// 只有房主可以添加机器人
+	guestClient.send(model.ReqAddBot, map[string]interface{}{})
+	assert.Equal(t, model.ErrCodeForbidden, guestClient.expect(model.MsgError)["code"])
+
+	ownerClient.send(model.ReqAddBot, map[string]interface{}{"level": "impossible"})
+	assert.Equal(t, model.ErrCodeBadRequest, ownerClient.expect(model.MsgError)["code"], "未知的难度")
+
+	ownerClient.send(model.ReqAddBot, map[string]interface{}{})
+	joined := ownerClient.expect(model.MsgPlayerJoined)["player"].(map[string]interface{})
+	assert.Equal(t, true, joined["isBot"])
+	assert.Equal(t, "机器人1", joined["name"])
+	assert.Equal(t, "normal", joined["botLevel"])
+	ownerClient.send(model.ReqAddBot, map[string]interface{}{"level": "hard"})
+	joined = ownerClient.expect(model.MsgPlayerJoined)["player"].(map[string]interface{})
+	assert.Equal(t, "hard", joined["botLevel"])
+	ownerClient.send(model.ReqAddBot, map[string]interface{}{})
+	assert.Equal(t, model.ErrCodeInvalidState, ownerClient.expect(model.MsgError)["code"], "房间已满")
+
+	// 真人离开后，剩下的真人和两个机器人打完一局
+	guestClient.send(model.ReqLeaveRoom, map[string]interface{}{})
+	ownerClient.expect(model.MsgPlayerLeft)
+	ownerClient.send(model.ReqStartGame, map[string]interface{}{})
+	ownerClient.expect(model.MsgGameStarted)
+	ownerClient.declareMissing()
+	ownerClient.playUntil(model.MsgGameOver)
+	waitRoom(gm, owner.RoomID)
+}
+
+// waitRoom 等房间处理完手头的消息：客户端收到消息时服务端可能还在同一次处理中，
+// 拿一次房间锁再结束测试，后面的测试修改配置时不会和它并发
+func waitRoom(gm *service.GameManager, roomID string) {
+	if room := gm.GetRoom(roomID); room != nil {
+		room.Lock()
+		room.Unlock()
+	}
+}
+
+// playUntil 只摸打、不碰不杠不胡地打下去，直到收到 msgType
+func (c *scriptedClient) playUntil(msgType string) {
+	c.t.Helper()
+	for step := 0; ; step++ {
+		require.Less(c.t, step, 2000, "没有等到 %s", msgType)
+		received, data := c.read()
+		switch received {
+		case model.MsgNewTile:
+			if data["playerID"] == c.playerID {
+				c.hand = append(c.hand, data["tile"].(string))
+			}
+		case model.MsgTurnChanged:
+			if data["playerID"] == c.playerID {
+				tile := c.discardChoices()[0]
+				c.hand, _ = rules.Remove(c.hand, tile, 1)
+				c.send(model.ReqPlayTile, map[string]interface{}{"tile": tile})
+			}
+		case model.MsgClaimWindow:
+			if options := data["options"].([]interface{}); len(options) > 0 {
+				c.send(model.ReqAction, map[string]interface{}{"action": model.ActionPass, "tiles": []string{}})
+			}
+		case model.MsgError:
+			c.t.Fatalf("请求被拒绝: %v", data)
+		}
+		if received == msgType {
+			return
+		}
+	}
+}
+
+// 对局中离开的玩家由机器人托管到本局结束，座位和手牌保持不变，打完之后才离开房间
+func TestLeavingMidHandIsAutoPlayedUntilTheHandEnds(t *testing.T) {
+	viper.Set("bot.thinkTime", time.Duration(0))
+	viper.Set("game.reconnectGrace", 50*time.Millisecond)
+	defer viper.Set("bot.thinkTime", nil)
+	defer viper.Set("game.reconnectGrace", nil)
+	gm := service.NewGameManager(store.NewMemory())
+	server := newTestServerWith(t, gm)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "玩家", "roomID": owner.RoomID})
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgRoomInfo)
+	guestClient := dialClient(t, server, guest)
+	guestClient.expect(model.MsgRoomInfo)
+
+	ownerClient.send(model.ReqStartGame, map[string]interface{}{})
+	ownerClient.expect(model.MsgGameStarted)
+	guestClient.expect(model.MsgGameStarted)
+	ownerClient.declareMissing()
+	guestClient.send(model.ReqLeaveRoom, map[string]interface{}{})
+
+	status := ownerClient.expect(model.MsgConnectionStatus)
+	assert.Equal(t, guest.PlayerID, status["playerID"])
+	assert.Equal(t, "left", status["reason"])
+	autoPlay := ownerClient.expect(model.MsgAutoPlay)
+	assert.Equal(t, guest.PlayerID, autoPlay["playerID"])
+	assert.Equal(t, "left", autoPlay["reason"])
+	guestClient.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := guestClient.conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	room := gm.GetRoom(owner.RoomID)
+	room.Lock()
+	assert.Len(t, room.Players, 2, "对局中离开不让出座位")
+	room.Unlock()
+
+	ownerClient.playUntil(model.MsgGameOver)
+	assert.Equal(t, guest.PlayerID, ownerClient.expect(model.MsgPlayerLeft)["playerID"])
+	room.Lock()
+	defer room.Unlock()
+	assert.Len(t, room.Players, 1)
+	assert.Equal(t, model.GameStateFinished, room.GameState)
+}
+
+// 观战者不占座位，只能聊天，收到的开局消息里没有手牌
+func TestSpectatorWatchesWithoutASeat(t *testing.T) {
+	server := newTestServer(t)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "玩家", "roomID": owner.RoomID})
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgRoomInfo)
+	guestClient := dialClient(t, server, guest)
+	guestClient.expect(model.MsgRoomInfo)
+	ownerClient.expect(model.MsgPlayerJoined)
+
+	watcher := postJSON(t, server.URL+"/api/room/spectate", map[string]string{"playerName": "观众", "roomID": owner.RoomID})
+	assert.Equal(t, float64(1), ownerClient.expect(model.MsgSpectators)["count"])
+	watcherClient := dialClient(t, server, watcher)
+	info := watcherClient.expect(model.MsgRoomInfo)
+	assert.Len(t, info["players"], 2, "观战者不在玩家列表中")
+	assert.Equal(t, float64(1), info["spectators"])
+
+	watcherClient.send(model.ReqStartGame, map[string]interface{}{})
+	assert.Equal(t, model.ErrCodeForbidden, watcherClient.expect(model.MsgError)["code"])
+	watcherClient.send(model.ReqChat, map[string]interface{}{"content": "加油"})
+	chat := ownerClient.expect(model.MsgChat)
+	assert.Equal(t, "观众", chat["playerName"])
+	assert.Equal(t, true, chat["spectator"])
+
+	ownerClient.send(model.ReqStartGame, map[string]interface{}{})
+	started := watcherClient.expect(model.MsgGameStarted)
+	assert.Empty(t, started["tiles"])
+	assert.Len(t, started["players"], 2)
+
+	watcherClient.send(model.ReqLeaveRoom, map[string]interface{}{})
+	assert.Equal(t, float64(0), ownerClient.expect(model.MsgSpectators)["count"])
+}
+
+// 对局进行中不能加入座位，只能观战
+func TestJoiningDuringAHandIsRefused(t *testing.T) {
+	gm := service.NewGameManager(store.NewMemory())
+	server := newTestServerWith(t, gm)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "玩家", "roomID": owner.RoomID})
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgRoomInfo)
+	guestClient := dialClient(t, server, guest)
+	guestClient.expect(model.MsgRoomInfo)
+	ownerClient.send(model.ReqStartGame, map[string]interface{}{})
+	ownerClient.expect(model.MsgGameStarted)
+
+	payload, err := json.Marshal(map[string]string{"playerName": "迟到", "roomID": owner.RoomID})
+	require.NoError(t, err)
+	resp, err := http.Post(server.URL+"/api/room/join", "application/json", bytes.NewReader(payload))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, true, body["spectate"], "提示客户端改为观战")
+
+	postJSON(t, server.URL+"/api/room/spectate", map[string]string{"playerName": "迟到", "roomID": owner.RoomID})
+	room := gm.GetRoom(owner.RoomID)
+	room.Lock()
+	defer room.Unlock()
+	assert.Len(t, room.Players, 2)
+	assert.Len(t, room.Spectators(), 1)
+}
+
+// postStatus 发送请求，只返回状态码
+func postStatus(t *testing.T, url string, body interface{}) int {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+	resp, err := http.Post(url, "application/json", bytes.NewReader(payload))
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// 教练观战默认关闭，需要教练密钥，座位上的玩家不能用自己的会话令牌给自己的房间当教练
+func TestCoachNeedsTheKeyAndIsRefusedToSeatedPlayers(t *testing.T) {
+	t.Cleanup(func() { viper.Set("game.coachKey", nil) })
+	gm := service.NewGameManager(store.NewMemory())
+	server := newTestServerWith(t, gm)
+	spectate := server.URL + "/api/room/spectate"
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	coach := func(roomID, key, session string) map[string]interface{} {
+		return map[string]interface{}{"playerName": "教练", "roomID": roomID, "coach": true, "coachKey": key, "session": session}
+	}
+	assert.Equal(t, http.StatusForbidden, postStatus(t, spectate, coach(owner.RoomID, "", "")), "没有配置密钥时不允许教练观战")
+
+	viper.Set("game.coachKey", "secret")
+	assert.Equal(t, http.StatusUnauthorized, postStatus(t, spectate, coach(owner.RoomID, "wrong", "")))
+	assert.Equal(t, http.StatusForbidden, postStatus(t, spectate, coach(owner.RoomID, "secret", owner.Token)), "带着座位上的会话令牌")
+
+	other := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "别的房主"})
+	trainee := postJSON(t, spectate, coach(other.RoomID, "secret", owner.Token))
+	room := gm.GetRoom(other.RoomID)
+	room.Lock()
+	require.Len(t, room.Spectators(), 1)
+	assert.True(t, room.Spectators()[0].Coach, "别的房间的令牌不影响")
+	room.Unlock()
+
+	join := server.URL + "/api/room/join"
+	assert.Equal(t, http.StatusForbidden, postStatus(t, join, map[string]string{"playerName": "教练", "roomID": other.RoomID, "session": trainee.Token}), "教练不能再坐到座位上")
+	assert.Equal(t, http.StatusOK, postStatus(t, join, map[string]string{"playerName": "玩家", "roomID": other.RoomID}), "同一个IP没有教练令牌的玩家可以加入")
+}
+
+// 没有配置信任的代理时，客户端伪造的 X-Forwarded-For 不能绕过失败次数限制
+func TestForwardedForDoesNotResetJoinFailures(t *testing.T) {
+	server := newTestServer(t)
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主", "password": "1234"})
+
+	status := 0
+	for i := 0; i < 11; i++ {
+		payload, err := json.Marshal(map[string]string{"playerName": "猜密码", "roomID": owner.RoomID, "password": fmt.Sprint(i)})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/api/room/join", bytes.NewReader(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", "10.0.0."+fmt.Sprint(i))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		status = resp.StatusCode
+	}
+	assert.Equal(t, http.StatusTooManyRequests, status)
+}
+
+// 机器人在锁外做决定，不再登记在座位上之后提交的请求直接丢弃，不会替已经接手的真人出牌
+func TestBotRequestsAreDroppedOnceTheBotLeavesTheSeat(t *testing.T) {
+	gm := service.NewGameManager(store.NewMemory())
+	room, err := gm.CreateRoom("", "")
+	require.NoError(t, err)
+	room.Lock()
+	for _, name := range []string{"A", "B"} {
+		room.AddPlayer(model.NewPlayer(name))
+	}
+	room.SetOwner(room.Players[0])
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+	for _, p := range room.Players {
+		require.NoError(t, room.HandleDingque(p.ID, rules.Suit(p.Tiles[0])))
+	}
+	dealer := room.Players[room.CurrentPlayerIndex]
+	table := &botTable{player: dealer, room: room, gameManager: gm}
+	table.bot = bot.New(dealer.ID, bot.Greedy{}, table)
+	t.Cleanup(func() {
+		room.Lock()
+		gm.CloseRoom(room, "测试结束")
+		room.Unlock()
+		table.bot.Close()
+	})
+	tiles := len(dealer.Tiles)
+	room.Unlock()
+
+	discard := func() {
+		room.Lock()
+		seq, tile := room.Seq(), dealer.Tiles[0]
+		room.Unlock()
+		table.Submit(model.ReqPlayTile, &model.PlayTileRequest{Tile: tile}, seq)
+	}
+	discard()
+	room.Lock()
+	assert.Len(t, dealer.Tiles, tiles, "没有登记的机器人不能出牌")
+	transport.For(room).StartAutoPlay(dealer.ID, table.bot)
+	room.Unlock()
+
+	discard()
+	room.Lock()
+	defer room.Unlock()
+	assert.Len(t, dealer.Tiles, tiles-1, "托管中的机器人可以出牌")
+}
+
+// 会话令牌只从子协议中读取，放在URL里的令牌不被接受，避免出现在访问日志中
+func TestSessionTokenIsNotAcceptedInTheURL(t *testing.T) {
+	server := newTestServer(t)
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + owner.RoomID + "?v=1&token=" + owner.Token
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	client := dialClient(t, server, owner)
+	client.expect(model.MsgRoomInfo)
+	assert.Empty(t, client.conn.Subprotocol())
+}
+
+// readProtobuf 读取一条protobuf消息直到收到指定类型，并把负载解码到data
+func TestDisconnectedPlayerIsAutoPlayedUntilReconnect(t *testing.T) {
+	viper.Set("bot.thinkTime", time.Duration(0))
+	defer viper.Set("bot.thinkTime", nil)
+	server := newTestServer(t)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "玩家", "roomID": owner.RoomID})
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgRoomInfo)
+	guestClient := dialClient(t, server, guest)
+	guestClient.expect(model.MsgRoomInfo)
+
+	// 开局前不能托管
+	guestClient.send(model.ReqAutoPlay, map[string]interface{}{"enabled": true})
+	assert.Equal(t, model.ErrCodeInvalidState, guestClient.expect(model.MsgError)["code"])
+
+	clients := map[string]*scriptedClient{owner.PlayerID: ownerClient, guest.PlayerID: guestClient}
+	dealerID := startHand(t, ownerClient, clients)
+
+	// 玩家掉线后由机器人托管，替他出牌
+	guestClient.conn.Close()
+	autoPlay := ownerClient.expect(model.MsgAutoPlay)
+	assert.Equal(t, guest.PlayerID, autoPlay["playerID"])
+	assert.Equal(t, true, autoPlay["enabled"])
+	assert.Equal(t, "disconnected", autoPlay["reason"])
+	if dealerID == owner.PlayerID {
+		tile := ownerClient.discardChoices()[0]
+		ownerClient.hand, _ = rules.Remove(ownerClient.hand, tile, 1)
+		ownerClient.send(model.ReqPlayTile, map[string]interface{}{"tile": tile})
+	}
+	for step := 0; ; step++ {
+		require.Less(t, step, 200, "托管的机器人没有出牌")
+		msgType, data := ownerClient.read()
+		if msgType == model.MsgTilePlayed && data["playerID"] == guest.PlayerID {
+			break
+		}
+		switch msgType {
+		case model.MsgNewTile:
+			if data["playerID"] == owner.PlayerID {
+				ownerClient.hand = append(ownerClient.hand, data["tile"].(string))
+			}
+		case model.MsgTurnChanged:
+			if data["playerID"] == owner.PlayerID {
+				tile := ownerClient.discardChoices()[0]
+				ownerClient.hand, _ = rules.Remove(ownerClient.hand, tile, 1)
+				ownerClient.send(model.ReqPlayTile, map[string]interface{}{"tile": tile})
+			}
+		case model.MsgClaimWindow:
+			if options := data["options"].([]interface{}); len(options) > 0 {
+				ownerClient.send(model.ReqAction, map[string]interface{}{"action": model.ActionPass, "tiles": []string{}})
+			}
+		case model.MsgGameOver:
+			t.Fatal("托管的机器人出牌之前对局就结束了")
+		}
+	}
+
+	// 重连后取消托管
+	guestClient = dialClient(t, server, guest)
+	for _, p := range guestClient.expect(model.MsgGameSnapshot)["players"].([]interface{}) {
+		assert.Nil(t, p.(map[string]interface{})["autoPlay"])
+	}
+	autoPlay = ownerClient.expect(model.MsgAutoPlay)
+	assert.Equal(t, guest.PlayerID, autoPlay["playerID"])
+	assert.Equal(t, false, autoPlay["enabled"])
+
+	// 也可以主动托管
+	guestClient.send(model.ReqAutoPlay, map[string]interface{}{"enabled": true})
+	autoPlay = ownerClient.expect(model.MsgAutoPlay)
+	assert.Equal(t, true, autoPlay["enabled"])
+	assert.Equal(t, "manual", autoPlay["reason"])
+}
+
+func readProtobuf(t *testing.T, conn *websocket.Conn, msgType string, data interface{}) model.Envelope {
+	t.Helper()
+	for {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		frameType, raw, err := conn.ReadMessage()
+		require.NoError(t, err, "等待 %s 时读取失败", msgType)
+		require.Equal(t, websocket.BinaryMessage, frameType)
+
+		// 服务端消息与客户端消息的外层字段相同，可以按Envelope解码
+		var message model.Envelope
+		require.NoError(t, codec.Unmarshal(raw, &message))
+		_, ok := model.FindMessageSpec(model.DirectionServer, message.Type)
+		require.True(t, ok, "服务端发出了目录中没有的消息类型 %q", message.Type)
+		if message.Type == msgType {
+			require.NoError(t, codec.Unmarshal(message.Data, data))
+			return message
+		}
+	}
+}
+
+func TestProtobufSubprotocolSharesRoomWithJSON(t *testing.T) {
+	server := newTestServer(t)
+
+	owner := postJSON(t, server.URL+"/api/room/create", map[string]string{"playerName": "房主"})
+	guest := postJSON(t, server.URL+"/api/room/join", map[string]string{"playerName": "手机玩家", "roomID": owner.RoomID})
+
+	ownerClient := dialClient(t, server, owner)
+	ownerClient.expect(model.MsgRoomInfo)
+
+	dialer := websocket.Dialer{Subprotocols: []string{codec.SubprotocolProtobuf, tokenSubprotocolPrefix + guest.Token}}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + guest.RoomID + "?v=1"
+	conn, _, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	require.Equal(t, codec.SubprotocolProtobuf, conn.Subprotocol(), "令牌不会被选为子协议")
+
+	var welcome model.WelcomeData
+	message := readProtobuf(t, conn, model.MsgWelcome, &welcome)
+	assert.Equal(t, model.ProtocolVersion, message.Version)
+	assert.Equal(t, guest.PlayerID, welcome.PlayerID)
+	var roomInfo model.RoomInfoData
+	readProtobuf(t, conn, model.MsgRoomInfo, &roomInfo)
+	assert.Len(t, roomInfo.Players, 2)
+
+	// protobuf客户端发出的聊天，JSON客户端照常收到
+	payload, err := codec.Marshal(model.ChatRequest{Content: "你好"})
+	require.NoError(t, err)
+	frame, err := codec.Marshal(model.Envelope{Type: model.ReqChat, Version: 1, Data: payload})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, frame))
+	chat := ownerClient.expect(model.MsgChat)
+	assert.Equal(t, "你好", chat["content"])
+
+	// JSON客户端的操作以二进制帧推送给protobuf客户端
+	ownerClient.send(model.ReqStartGame, nil)
+	var started model.GameStartedData
+	readProtobuf(t, conn, model.MsgGameStarted, &started)
+	assert.Equal(t, model.GameStatePlaying, started.GameState)
+	assert.NotEmpty(t, started.Tiles)
+
+	// 格式错误的负载返回protobuf编码的错误消息
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, []byte{0xff}))
+	var errData model.ErrorData
+	readProtobuf(t, conn, model.MsgError, &errData)
+	assert.Equal(t, model.ErrCodeBadRequest, errData.Code)
+}
+
+func TestProtocolEndpointListsCatalogue(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/api/protocol")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var doc struct {
+		ProtocolVersion int `json:"protocolVersion"`
+		Messages        []struct {
+			Type      string                 `json:"type"`
+			Direction string                 `json:"direction"`
+			Schema    map[string]interface{} `json:"schema"`
+		} `json:"messages"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	assert.Equal(t, model.ProtocolVersion, doc.ProtocolVersion)
+	require.Len(t, doc.Messages, len(model.Catalogue))
+	for _, m := range doc.Messages {
+		assert.Equal(t, "object", m.Schema["type"], m.Type)
+	}
+}
+
+func TestReplayEndpointRejectsUnknownReplay(t *testing.T) {
+	server := newTestServer(t)
+
+	for _, id := range []string{"00000000-0000-0000-0000-000000000000", "..%2Fconfig"} {
+		resp, err := http.Get(server.URL + "/api/replays/" + id)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, id)
+	}
+}
+
+func TestImportAndExportMJAI(t *testing.T) {
+	server := newTestServer(t)
+
+	// 庄家起手14张就胡（天胡），另一家定缺后什么都不用做
+	log := strings.Join([]string{
+		`{"type":"start_game","names":["庄家","闲家"]}`,
+		`{"type":"start_kyoku","bakaze":"E","kyoku":1,"honba":0,"kyotaku":0,"oya":0,"dora_marker":"?","scores":[0,0],` +
+			`"tehais":[["1m","2m","3m","4m","5m","6m","7m","8m","9m","1p","2p","3p","5p"],` +
+			`["1s","2s","3s","4s","5s","6s","7s","8s","9s","6p","7p","8p","9p"]]}`,
+		`{"type":"tsumo","actor":0,"pai":"5p"}`,
+		`{"type":"dingque","actor":0,"suit":"s"}`,
+		`{"type":"dingque","actor":1,"suit":"m"}`,
+		`{"type":"hora","actor":0,"target":0,"pai":"5p","deltas":[2,-2],"fan":1,"patterns":["平胡","自摸"]}`,
+		`{"type":"end_kyoku"}`,
+		`{"type":"end_game"}`,
+	}, "\n")
+
+	resp, err := http.Post(server.URL+"/api/replays/import", "application/x-ndjson", strings.NewReader(log))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var imported struct {
+		ReplayIDs []string `json:"replayIDs"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&imported))
+	require.Len(t, imported.ReplayIDs, 1)
+
+	resp, err = http.Get(server.URL + "/api/replays/" + imported.ReplayIDs[0])
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var replay model.Replay
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&replay))
+	assert.Equal(t, 2, replay.FinalScores[replay.Seats[0].ID])
+	assert.Equal(t, "庄家", replay.Seats[0].Name)
+
+	resp, err = http.Get(server.URL + "/api/replays/" + imported.ReplayIDs[0] + "/mjai")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	exported := new(bytes.Buffer)
+	_, err = exported.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, len(strings.Split(log, "\n")), strings.Count(exported.String(), "\n"))
+	assert.Contains(t, exported.String(), `"type":"hora"`)
+
+	// 日本麻将的牌谱（有字牌）不能导入
+	resp, err = http.Post(server.URL+"/api/replays/import", "application/x-ndjson", strings.NewReader(strings.Replace(log, `"5p"]`, `"E"]`, 1)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// 静态扫描网页客户端，确保它收发的消息类型都在协议目录中
+func TestWebClientUsesCatalogueMessageTypes(t *testing.T) {
+	source, err := os.ReadFile("../static/js/room.js")
+	require.NoError(t, err)
+	js := string(source)
+
+	// handleMessage 中处理的服务端消息
+	start := strings.Index(js, "function handleMessage(")
+	require.NotEqual(t, -1, start)
+	end := strings.Index(js[start:], "\n}\n")
+	require.NotEqual(t, -1, end)
+	handled := regexp.MustCompile(`case '([a-z_]+)':`).FindAllStringSubmatch(js[start:start+end], -1)
+	require.NotEmpty(t, handled)
+	for _, m := range handled {
+		_, ok := model.FindMessageSpec(model.DirectionServer, m[1])
+		assert.True(t, ok, "客户端处理了服务端不会发送的消息 %q", m[1])
+	}
+
+	// 客户端发出的请求
+	sent := regexp.MustCompile(`(?:sendMessage\(|type: )'([a-z_]+)'`).FindAllStringSubmatch(js, -1)
+	require.NotEmpty(t, sent)
+	for _, m := range sent {
+		_, ok := model.FindMessageSpec(model.DirectionClient, m[1])
+		assert.True(t, ok, "客户端发送了服务端不认识的消息 %q", m[1])
+	}
+}
+
+// validateSchema 按 model.JSONSchema 生成的子集校验数据
+func validateSchema(schema map[string]interface{}, value interface{}, path string) error {
+	if !matchesType(schema["type"], value) {
+		return fmt.Errorf("%s: 期望类型 %v，实际为 %T", path, schema["type"], value)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]string); ok {
+			for _, name := range required {
+				if _, ok := v[name]; !ok {
+					return fmt.Errorf("%s: 缺少必填字段 %s", path, name)
+				}
+			}
+		}
+		for name, fieldValue := range v {
+			fieldSchema, ok := properties[name].(map[string]interface{})
+			if !ok {
+				if extra, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+					fieldSchema = extra
+				} else if schema["additionalProperties"] == false {
+					return fmt.Errorf("%s: 未声明的字段 %s", path, name)
+				} else {
+					continue
+				}
+			}
+			if err := validateSchema(fieldSchema, fieldValue, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range v {
+			if err := validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func matchesType(expected interface{}, value interface{}) bool {
+	switch t := expected.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		for _, option := range t {
+			if matchesType(option, value) {
+				return true
+			}
+		}
+		return false
+	case string:
+		switch t {
+		case "null":
+			return value == nil
+		case "object":
+			_, ok := value.(map[string]interface{})
+			return ok
+		case "array":
+			_, ok := value.([]interface{})
+			return ok
+		case "string":
+			_, ok := value.(string)
+			return ok
+		case "boolean":
+			_, ok := value.(bool)
+			return ok
+		case "integer":
+			n, ok := value.(float64)
+			return ok && n == float64(int64(n))
+		case "number":
+			_, ok := value.(float64)
+			return ok
+		}
+	}
+	return false
+}
@@ -35,3 +35,11 @@ func JoinRoomHandler(c *gin.Context) {
 		"title": "四川麻将-加入房间",
 	})
 }
+
+// ReplayHandler 处理回放页面请求
+func ReplayHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "replay.html", gin.H{
+		"title":    "四川麻将-回放",
+		"replayID": c.Param("replayID"),
+	})
+}
@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"goMahjong/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProtocolHandler 返回WebSocket消息目录，每种消息的数据结构用JSON Schema描述
+func ProtocolHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, model.ProtocolDocument())
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"goMahjong/config"
+	"goMahjong/mjai"
+	"goMahjong/model"
+	"goMahjong/service"
+	"goMahjong/store"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// ReplayAPIHandler 返回指定ID的回放文件
+func ReplayAPIHandler(gameManager *service.GameManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		replay, ok := findReplay(c, gameManager)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, replay)
+	}
+}
+
+// ExportMJAIHandler 把回放导出为MJAI日志，每行一个JSON事件
+func ExportMJAIHandler(gameManager *service.GameManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		replay, ok := findReplay(c, gameManager)
+		if !ok {
+			return
+		}
+
+		c.Header("Content-Type", "application/x-ndjson; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="`+replay.ID+`.mjson"`)
+		c.Status(http.StatusOK)
+		if err := mjai.Write(c.Writer, replay); err != nil {
+			config.GetZapLogger().Error("导出MJAI日志失败: " + err.Error())
+		}
+	}
+}
+
+// ImportMJAIHandler 导入MJAI日志，每一局保存为一个回放，返回回放ID
+func ImportMJAIHandler(gameManager *service.GameManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := http.MaxBytesReader(c.Writer, c.Request.Body, viper.GetInt64("replay.maxImportSize"))
+		replays, err := mjai.Read(body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无法导入牌谱: " + err.Error()})
+			return
+		}
+
+		ids := make([]string, 0, len(replays))
+		for _, replay := range replays {
+			if err := gameManager.SaveReplay(replay); err != nil {
+				config.GetZapLogger().Error("保存导入的回放失败: " + err.Error())
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "保存回放失败"})
+				return
+			}
+			ids = append(ids, replay.ID)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"replayIDs": ids})
+	}
+}
+
+// findReplay 按路径中的ID查找回放，找不到时写出错误响应
+func findReplay(c *gin.Context, gameManager *service.GameManager) (*model.Replay, bool) {
+	replay, err := gameManager.GetReplay(c.Param("replayID"))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "回放不存在"})
+		return nil, false
+	}
+	if err != nil {
+		config.GetZapLogger().Error("读取回放失败: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "读取回放失败"})
+		return nil, false
+	}
+	return replay, true
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"goMahjong/model"
 	"goMahjong/service"
 	"net/http"
@@ -21,7 +22,19 @@ func CreateRoomAPIHandler(gameManager *service.GameManager) gin.HandlerFunc {
 			return
 		}
 
-		room, err := gameManager.CreateRoom(req.Password)
+		room, err := gameManager.CreateRoom(req.Password, c.ClientIP())
+		if errors.Is(err, service.ErrDraining) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "服务器即将停止，暂时不能创建房间"})
+			return
+		}
+		if errors.Is(err, service.ErrTooManyRooms) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "房间数量已达上限，请稍后再试"})
+			return
+		}
+		if errors.Is(err, service.ErrTooManyRoomsForIP) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "创建的房间太多，请先关闭不用的房间"})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "创建房间失败"})
 			return
@@ -31,29 +44,40 @@ func CreateRoomAPIHandler(gameManager *service.GameManager) gin.HandlerFunc {
 		player := model.NewPlayer(req.PlayerName)
 
 		// 房间添加玩家并设置玩家为房主
+		room.Lock()
 		room.AddPlayer(player)
 		room.SetOwner(player)
+		gameManager.SaveRoom(room)
+		room.Unlock()
 
 		c.JSON(http.StatusOK, gin.H{
 			"roomID":   room.ID,
 			"playerID": player.ID,
+			"token":    gameManager.IssueSession(room.ID, player.ID),
 		})
 	}
 }
 
-// JoinRoomAPIHandler 处理加入房间API请求
-func JoinRoomAPIHandler(gameManager *service.GameManager) gin.HandlerFunc {
+// JoinRoomAPIHandler 处理加入房间API请求，按IP限制房间号和密码的失败次数
+func JoinRoomAPIHandler(gameManager *service.GameManager, limiter *service.AttemptLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		clientIP := c.ClientIP()
+
 		var req struct {
 			RoomID     string `json:"roomID" binding:"required"`
 			PlayerName string `json:"playerName" binding:"required"`
 			Password   string `json:"password"`
+			Session    string `json:"session"`
 		}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
 			return
 		}
+		if !limiter.Attempt(clientIP, req.RoomID) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "尝试次数过多，请稍后再试"})
+			return
+		}
 
 		room := gameManager.GetRoom(req.RoomID)
 		if room == nil {
@@ -61,22 +85,37 @@ func JoinRoomAPIHandler(gameManager *service.GameManager) gin.HandlerFunc {
 			return
 		}
 
-		if room.Password != req.Password {
+		if !room.CheckPassword(req.Password) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "密码错误"})
 			return
 		}
+		limiter.Succeed(clientIP, req.RoomID)
 
-		if len(room.Players) >= 4 {
+		room.Lock()
+		defer room.Unlock()
+
+		// 对局中途加入的玩家没有手牌，也不在这一局的事件里，只能观战
+		if room.GameState == model.GameStatePlaying {
+			c.JSON(http.StatusConflict, gin.H{"error": "对局进行中，不能加入", "spectate": true})
+			return
+		}
+		if len(room.Players) >= model.MaxPlayers {
 			c.JSON(http.StatusForbidden, gin.H{"error": "房间已满"})
 			return
 		}
+		if coachingRoom(room, gameManager, req.Session) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "教练不能加入这个房间的座位"})
+			return
+		}
 
 		player := model.NewPlayer(req.PlayerName)
 		room.AddPlayer(player)
+		gameManager.SaveRoom(room)
 
 		c.JSON(http.StatusOK, gin.H{
 			"roomID":   room.ID,
 			"playerID": player.ID,
+			"token":    gameManager.IssueSession(room.ID, player.ID),
 		})
 	}
 }
@@ -89,11 +128,13 @@ func GetRoomsHandler(gameManager *service.GameManager) gin.HandlerFunc {
 		// 转换为前端需要的格式
 		roomsData := make([]gin.H, 0, len(rooms))
 		for _, room := range rooms {
+			room.Lock()
 			roomData := gin.H{
-				"id":          room.ID,
-				"playerCount": len(room.Players),
-				"gameState":   room.GameState,
-				"hasPassword": room.Password != "",
+				"id":             room.ID,
+				"playerCount":    len(room.Players),
+				"spectatorCount": len(room.Spectators()),
+				"gameState":      room.GameState,
+				"hasPassword":    room.HasPassword(),
 			}
 
 			// 添加房主信息
@@ -103,6 +144,7 @@ func GetRoomsHandler(gameManager *service.GameManager) gin.HandlerFunc {
 					"name": room.Owner.Name,
 				}
 			}
+			room.Unlock()
 			roomsData = append(roomsData, roomData)
 		}
 
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"goMahjong/config"
+	"goMahjong/model"
+	"goMahjong/service"
+	"goMahjong/transport"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// drainPollInterval 停服时检查对局是否都已结束的间隔
+const drainPollInterval = 500 * time.Millisecond
+
+// DrainRooms 停服前排空房间：不再创建房间和开始新的一局，通知所有玩家倒计时；
+// 进行中的对局都结束或倒计时结束后保存检查点，再发送关闭帧断开所有连接
+// 没打完的对局在下次启动时恢复，玩家重连后继续
+func DrainRooms(ctx context.Context, gameManager *service.GameManager, countdown time.Duration) {
+	logger := config.GetZapLogger()
+
+	deadline := time.Now().Add(countdown)
+	gameManager.StartDraining(deadline)
+	logger.Info("服务器将在 " + countdown.String() + " 后停止")
+	for _, room := range gameManager.GetAllRooms() {
+		room.Lock()
+		notifyShutdown(room, nil, deadline)
+		room.Unlock()
+	}
+
+	timer := time.NewTimer(countdown)
+	defer timer.Stop()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+wait:
+	for playingRooms(gameManager) > 0 {
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			logger.Info("倒计时结束，还有 " + strconv.Itoa(playingRooms(gameManager)) + " 个房间的对局没有结束")
+			break wait
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	gameManager.Checkpoint()
+
+	closed := make([]<-chan struct{}, 0)
+	for _, room := range gameManager.GetAllRooms() {
+		room.Lock()
+		for _, player := range room.Recipients() {
+			if conn, ok := transport.For(room).Conn(player.ID).(*transport.Connection); ok {
+				conn.Shutdown(websocket.CloseGoingAway, "服务器停止")
+				closed = append(closed, conn.Done())
+			}
+		}
+		room.Unlock()
+	}
+	// 等关闭帧写出，超时的连接随进程退出
+	for _, done := range closed {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return
+		}
+	}
+	logger.Info("已断开所有连接")
+}
+
+// notifyShutdown 通知玩家服务器即将停止，player 为nil时通知整个房间，调用方需持有房间锁
+func notifyShutdown(room *model.Room, player *model.Player, deadline time.Time) {
+	message := model.Message{
+		Type: model.MsgServerShutdown,
+		Data: model.ServerShutdownData{Seconds: int(math.Ceil(time.Until(deadline).Seconds()))},
+	}
+	if player == nil {
+		room.BroadcastAll(message)
+	} else {
+		room.SendDirect(player, message)
+	}
+}
+
+// playingRooms 返回对局还在进行的房间数
+func playingRooms(gameManager *service.GameManager) int {
+	count := 0
+	for _, room := range gameManager.GetAllRooms() {
+		room.Lock()
+		if room.GameState == model.GameStatePlaying {
+			count++
+		}
+		room.Unlock()
+	}
+	return count
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"goMahjong/config"
+	"goMahjong/model"
+	"goMahjong/service"
+	"goMahjong/transport"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// SpectateRoomAPIHandler 处理观战API请求，观战者不占座位，和加入房间共用失败次数限制
+// coach 为 true 时以教练身份观战，可以看到延迟公开的所有人手牌，需要服务器配置的教练密钥 coachKey；
+// session 是浏览器里已有的会话令牌，用来认出想在另一个标签页里给自己当教练的玩家
+func SpectateRoomAPIHandler(gameManager *service.GameManager, limiter *service.AttemptLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientIP := c.ClientIP()
+
+		var req struct {
+			RoomID     string `json:"roomID" binding:"required"`
+			PlayerName string `json:"playerName" binding:"required"`
+			Password   string `json:"password"`
+			Coach      bool   `json:"coach"`
+			CoachKey   string `json:"coachKey"`
+			Session    string `json:"session"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+			return
+		}
+		// 教练能看到所有人的手牌，需要服务器配置的教练密钥，没有配置时不允许教练观战
+		coachKey := viper.GetString("game.coachKey")
+		if req.Coach && coachKey == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "这个服务器不允许教练观战"})
+			return
+		}
+		if !limiter.Attempt(clientIP, req.RoomID) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "尝试次数过多，请稍后再试"})
+			return
+		}
+
+		room := gameManager.GetRoom(req.RoomID)
+		if room == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "房间不存在"})
+			return
+		}
+
+		if !room.CheckPassword(req.Password) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "密码错误"})
+			return
+		}
+		if req.Coach && subtle.ConstantTimeCompare([]byte(req.CoachKey), []byte(coachKey)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "教练密钥错误"})
+			return
+		}
+		limiter.Succeed(clientIP, req.RoomID)
+
+		room.Lock()
+		defer room.Unlock()
+
+		if req.Coach && seatedInRoom(room, gameManager, req.Session) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "对局中的玩家不能当这个房间的教练"})
+			return
+		}
+
+		spectator := model.NewPlayer(req.PlayerName)
+		spectator.Coach = req.Coach
+		if err := room.AddSpectator(spectator); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		room.BroadcastSpectators()
+		// 一直没有连上的观战者超时后移除
+		holdSpectator(spectator, room)
+
+		c.JSON(http.StatusOK, gin.H{
+			"roomID":   room.ID,
+			"playerID": spectator.ID,
+			"token":    gameManager.IssueSession(room.ID, spectator.ID),
+		})
+	}
+}
+
+// seatedInRoom 请求是否带着这个房间座位上的会话令牌，座位上的玩家不能在另一个标签页里给自己的房间当教练
+// 客户端IP可以经代理伪造，只按会话令牌判断，调用方需持有房间锁
+func seatedInRoom(room *model.Room, gameManager *service.GameManager, session string) bool {
+	roomID, playerID, err := gameManager.VerifySession(session)
+	return err == nil && roomID == room.ID && room.GetPlayer(playerID) != nil
+}
+
+// coachingRoom 请求是否带着这个房间教练的会话令牌，教练不能再坐到这个房间的座位上，调用方需持有房间锁
+func coachingRoom(room *model.Room, gameManager *service.GameManager, session string) bool {
+	roomID, playerID, err := gameManager.VerifySession(session)
+	if err != nil || roomID != room.ID {
+		return false
+	}
+	spectator := room.GetSpectator(playerID)
+	return spectator != nil && spectator.Coach
+}
+
+// attachSpectator 把连接交给观战者并发送房间当前的公开状态，调用方需持有房间锁
+func attachSpectator(conn *transport.Connection, spectator *model.Player, room *model.Room, gameManager *service.GameManager) {
+	clients := transport.For(room)
+	clients.Release(spectator.ID)
+	clients.Attach(spectator.ID, conn)
+	spectator.Connected = true
+	config.GetZapLogger().Info("观战者 " + spectator.Name + " 已连接到房间 " + room.ID)
+
+	room.SendDirect(spectator, model.Message{
+		Type: model.MsgWelcome,
+		Data: model.WelcomeData{ProtocolVersion: conn.Version(), PlayerID: spectator.ID},
+	})
+	// 不在座位上的人得到的是观战视图
+	sendRoomState(spectator, room)
+	if deadline, draining := gameManager.DrainDeadline(); draining {
+		notifyShutdown(room, spectator, deadline)
+	}
+
+	go handlePlayerMessages(conn, spectator, room, gameManager)
+}
+
+// 处理一条观战者的消息，观战者只能聊天、补发消息和离开，调用方需持有房间锁
+func handleSpectatorMessage(spectator *model.Player, room *model.Room, req interface{}) (bool, error) {
+	switch req := req.(type) {
+	case *model.ChatRequest:
+		room.BroadcastAll(model.Message{
+			Type: model.MsgChat,
+			Data: model.ChatData{
+				PlayerID:   spectator.ID,
+				PlayerName: spectator.Name,
+				Content:    req.Content,
+				Spectator:  true,
+			},
+		})
+	case *model.ResyncRequest:
+		if messages, ok := room.Replay(spectator.ID, req.AfterSeq); ok {
+			for _, message := range messages {
+				room.Transport.Deliver(spectator.ID, message)
+			}
+		} else {
+			sendRoomState(spectator, room)
+		}
+	case *model.LeaveRoomRequest:
+		removeSpectator(spectator, room)
+		return true, nil
+	default:
+		return false, model.NewGameError(model.ErrCodeForbidden, "观战者不能参与对局")
+	}
+	return false, nil
+}
+
+// holdSpectator 观战者掉线后保留一段时间，让刷新页面的观战者回到房间，调用方需持有房间锁
+func holdSpectator(spectator *model.Player, room *model.Room) {
+	transport.For(room).Hold(spectator.ID, viper.GetDuration("game.reconnectGrace"), func() {
+		room.Lock()
+		defer room.Unlock()
+
+		if spectator.Connected || room.GetSpectator(spectator.ID) == nil {
+			return
+		}
+		removeSpectator(spectator, room)
+	})
+}
+
+// removeSpectator 移除观战者并通知房间，调用方需持有房间锁
+func removeSpectator(spectator *model.Player, room *model.Room) {
+	config.GetZapLogger().Info("观战者离开房间: " + spectator.Name)
+
+	transport.For(room).Remove(spectator.ID)
+	room.RemoveSpectator(spectator.ID)
+	room.BroadcastSpectators()
+}
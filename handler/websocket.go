@@ -1,20 +1,30 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
+	"goMahjong/codec"
 	"goMahjong/config"
 	"goMahjong/model"
 	"goMahjong/service"
+	"goMahjong/transport"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
 )
 
+// tokenSubprotocolPrefix 客户端通过 Sec-WebSocket-Protocol 携带会话令牌时使用的前缀，
+// 令牌不放在URL里，避免被访问日志和代理记录下来；服务端只从中读取令牌，不会选中这个子协议
+const tokenSubprotocolPrefix = "mahjong.token."
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Subprotocols:    codec.Subprotocols(),
 	CheckOrigin: func(r *http.Request) bool {
 		return true // 允许所有跨域请求
 	},
@@ -24,130 +34,386 @@ var upgrader = websocket.Upgrader{
 func HandleWebSocket(c *gin.Context, gameManager *service.GameManager) {
 	logger := config.GetZapLogger()
 	roomID := c.Param("roomID")
-	playerID := c.Query("playerID")
+	token := sessionToken(c.Request)
 
-	if roomID == "" || playerID == "" {
+	if roomID == "" || token == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少必要参数"})
 		return
 	}
 
+	// 玩家身份只来自会话令牌，不信任客户端传入的playerID
+	tokenRoomID, playerID, err := gameManager.VerifySession(token)
+	if err != nil || tokenRoomID != roomID {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "身份验证失败"})
+		return
+	}
+
+	// 协商协议版本，客户端通过 v 参数声明自己支持的最高版本
+	clientVersion, _ := strconv.Atoi(c.Query("v"))
+	version, ok := model.NegotiateVersion(clientVersion)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不支持的协议版本"})
+		return
+	}
+
 	room := gameManager.GetRoom(roomID)
 	if room == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "房间不存在"})
 		return
 	}
 
+	room.Lock()
 	player := room.GetPlayer(playerID)
+	spectating := false
+	if player == nil {
+		player = room.GetSpectator(playerID)
+		spectating = player != nil
+	}
+	room.Unlock()
 	if player == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "玩家不存在"})
 		return
 	}
 
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		logger.Error("WebSocket升级失败: " + err.Error())
 		return
 	}
+	conn := transport.NewConnection(ws, version, codec.ForSubprotocol(ws.Subprotocol()))
 
-	// 将连接保存到玩家对象
-	player.Conn = conn
-	logger.Info("玩家 " + player.Name + " 已连接到房间 " + roomID)
+	room.Lock()
+	defer room.Unlock()
 
-	// 发送房间信息给新连接的玩家
-	roomInfo := room.GetRoomInfo()
-	player.SendMessage(model.Message{
-		Type: "room_info",
-		Data: roomInfo,
-	})
+	// 升级期间玩家可能已经被移出房间
+	if spectating {
+		if room.GetSpectator(playerID) == nil {
+			conn.Close()
+			return
+		}
+		attachSpectator(conn, player, room, gameManager)
+		return
+	}
+	if room.GetPlayer(playerID) == nil {
+		conn.Close()
+		return
+	}
 
-	// 通知房间其他玩家有新玩家加入
-	room.BroadcastExcept(model.Message{
-		Type: "player_joined",
-		Data: map[string]interface{}{
-			"player": player.GetPublicInfo(),
-		},
-	}, player.ID)
+	// 登记玩家的连接，同一玩家的旧连接直接关闭
+	clients := transport.For(room)
+	resumed := clients.Holding(player.ID)
+	clients.Release(player.ID)
+	clients.Attach(player.ID, conn)
+	player.Connected = true
 
-	// 设置连接关闭时的处理函数
-	conn.SetCloseHandler(func(code int, text string) error {
-		handlePlayerLeave(player, room, gameManager)
-		return nil
+	// 先通知房间其他玩家，新连接的玩家随后收到的状态已经包含这些变化
+	if resumed {
+		logger.Info("玩家 " + player.Name + " 重新连接到房间 " + roomID)
+	} else {
+		logger.Info("玩家 " + player.Name + " 已连接到房间 " + roomID)
+
+		// 通知房间其他玩家有新玩家加入
+		room.BroadcastExcept(model.Message{
+			Type: model.MsgPlayerJoined,
+			Data: model.PlayerJoinedData{Player: player.GetPublicInfo()},
+		}, player.ID)
+	}
+	broadcastConnectionStatus(room, player, "")
+
+	// 掉线期间的托管在重连后取消，快照里已经是取消后的状态
+	if resumed {
+		setAutoPlay(player, room, gameManager, false, "")
+	}
+
+	// 告知客户端协商好的协议版本
+	room.SendDirect(player, model.Message{
+		Type: model.MsgWelcome,
+		Data: model.WelcomeData{ProtocolVersion: version, PlayerID: player.ID},
 	})
 
+	// 发送房间信息和对局状态给新连接的玩家
+	sendRoomState(player, room)
+	if deadline, draining := gameManager.DrainDeadline(); draining {
+		notifyShutdown(room, player, deadline)
+	}
+
 	// 处理来自客户端的消息
-	go handlePlayerMessages(player, room, gameManager)
+	go handlePlayerMessages(conn, player, room, gameManager)
+}
+
+// sessionToken 从握手请求声明的子协议中取出会话令牌
+func sessionToken(r *http.Request) string {
+	for _, protocol := range websocket.Subprotocols(r) {
+		if token, ok := strings.CutPrefix(protocol, tokenSubprotocolPrefix); ok {
+			return token
+		}
+	}
+	return ""
 }
 
 // 处理来自玩家的消息
-func handlePlayerMessages(player *model.Player, room *model.Room, gameManager *service.GameManager) {
-	var message model.Message
+func handlePlayerMessages(conn *transport.Connection, player *model.Player, room *model.Room, gameManager *service.GameManager) {
 	logger := config.GetZapLogger()
-	defer func() {
-		if r := recover(); r != nil {
-			logger.Error("处理玩家消息时发生错误")
-		}
-	}()
+	defer conn.Close()
 
 	for {
-		_, msg, err := player.Conn.ReadMessage()
+		msg, err := conn.ReadMessage()
 		if err != nil {
-			handlePlayerLeave(player, room, gameManager)
+			room.Lock()
+			// 玩家已经用新连接替换了这个连接，不算掉线
+			if transport.For(room).Conn(player.ID) == conn {
+				handlePlayerDisconnect(player, room, gameManager, err)
+			}
+			room.Unlock()
 			break
 		}
 
-		if err := json.Unmarshal(msg, &message); err != nil {
-			logger.Error("解析消息失败: " + err.Error())
+		envelope, err := conn.Codec().Decode(msg)
+		if err != nil {
+			logger.Warn("解析消息失败: " + err.Error())
+			conn.Send(errorMessage("", model.NewGameError(model.ErrCodeBadRequest, "无法解析的消息")))
 			continue
 		}
 
-		// 根据消息类型处理不同的游戏逻辑
-		switch message.Type {
-		case "chat":
-			// 处理聊天消息
-			if content, ok := message.Data.(map[string]interface{})["content"].(string); ok {
-				room.BroadcastAll(model.Message{
-					Type: "chat",
-					Data: map[string]interface{}{
-						"playerID":   player.ID,
-						"playerName": player.Name,
-						"content":    content,
-					},
-				})
-			}
-		case "game_start":
-			// 只有房主可以开始游戏
-			if player.ID == room.Owner.ID {
-				if room.GameState == model.GameStateWaiting && len(room.Players) >= 2 {
-					room.StartGame()
-					room.BroadcastAll(model.Message{
-						Type: "game_started",
-						Data: room.GetGameState(),
-					})
-				}
-			}
-		case "play_tile":
-			// 处理出牌
-			if data, ok := message.Data.(map[string]interface{}); ok {
-				if tileStr, ok := data["tile"].(string); ok {
-					room.HandlePlayTile(player.ID, tileStr)
-				}
-			}
-		case "action":
-			// 处理玩家动作（吃、碰、杠、胡）
-			if data, ok := message.Data.(map[string]interface{}); ok {
-				actionType, _ := data["action"].(string)
-				tiles, _ := data["tiles"].([]interface{})
-				room.HandlePlayerAction(player.ID, actionType, tiles)
-			}
-		case "leave_room":
-			// 玩家主动离开房间
-			handlePlayerLeave(player, room, gameManager)
+		req, err := model.DecodeRequest(envelope, conn.Version(), conn.Codec())
+		if err != nil {
+			conn.Send(errorMessage(envelope.Type, err))
+			continue
+		}
+
+		if handlePlayerMessageLocked(player, room, gameManager, envelope, req) {
 			return
 		}
 	}
 }
 
-// 处理玩家离开的函数
+// 加锁处理一条玩家消息，处理过程中panic也能释放房间锁，不会中断读协程
+func handlePlayerMessageLocked(player *model.Player, room *model.Room, gameManager *service.GameManager, envelope model.Envelope, req interface{}) (left bool) {
+	room.Lock()
+	defer room.Unlock()
+
+	left, err := handlePlayerMessageSafely(player, room, gameManager, envelope, req)
+	if err != nil {
+		sendError(player, room, envelope.Type, err)
+	}
+	return left
+}
+
+// 处理一条玩家消息，处理过程中的panic转为内部错误返回，调用方需持有房间锁
+func handlePlayerMessageSafely(player *model.Player, room *model.Room, gameManager *service.GameManager, envelope model.Envelope, req interface{}) (left bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			config.GetZapLogger().Error(fmt.Sprintf("处理玩家消息 %s 时发生错误: %v", envelope.Type, r))
+			left, err = false, model.NewGameError(model.ErrCodeInternal, "服务器内部错误")
+		}
+	}()
+	return handlePlayerMessage(player, room, gameManager, envelope.Seq, req)
+}
+
+// 处理一条玩家消息，调用方需持有房间锁；返回true表示玩家已离开房间
+// seq 是客户端收到的最后一个序号，对局操作会据此拒绝过期请求
+func handlePlayerMessage(player *model.Player, room *model.Room, gameManager *service.GameManager, seq uint64, req interface{}) (bool, error) {
+	if room.GetSpectator(player.ID) != nil {
+		return handleSpectatorMessage(player, room, req)
+	}
+
+	// 根据消息类型处理不同的游戏逻辑
+	switch req := req.(type) {
+	case *model.ChatRequest:
+		// 处理聊天消息
+		room.BroadcastAll(model.Message{
+			Type: model.MsgChat,
+			Data: model.ChatData{
+				PlayerID:   player.ID,
+				PlayerName: player.Name,
+				Content:    req.Content,
+			},
+		})
+	case *model.StartGameRequest:
+		// 停服期间不再开始新的一局
+		if gameManager.Draining() {
+			return false, model.NewGameError(model.ErrCodeInvalidState, "服务器即将停止，不能开始新的一局")
+		}
+		// 只有房主可以开始游戏
+		if _, err := room.Execute(model.StartGameCommand{PlayerID: player.ID}); err != nil {
+			return false, err
+		}
+		gameManager.SaveRoom(room)
+	case *model.PlayTileRequest:
+		// 处理出牌
+		if err := room.CheckFresh(seq); err != nil {
+			return false, err
+		}
+		_, err := room.Execute(model.PlayTileCommand{PlayerID: player.ID, Tile: req.Tile})
+		return false, err
+	case *model.ActionRequest:
+		// 处理玩家动作（碰、杠、胡、过）
+		if err := room.CheckFresh(seq); err != nil {
+			return false, err
+		}
+		_, err := room.Execute(model.ActionCommand{PlayerID: player.ID, Action: req.Action, Tiles: req.Tiles})
+		return false, err
+	case *model.DingqueRequest:
+		// 开局定缺
+		_, err := room.Execute(model.DingqueCommand{PlayerID: player.ID, Suit: req.Suit})
+		return false, err
+	case *model.ResyncRequest:
+		// 补发错过的消息，记录不够时改发完整状态
+		if messages, ok := room.Replay(player.ID, req.AfterSeq); ok {
+			for _, message := range messages {
+				room.Transport.Deliver(player.ID, message)
+			}
+		} else {
+			sendRoomState(player, room)
+		}
+	case *model.AddBotRequest:
+		// 房主在开局前添加机器人
+		return false, addBot(player, room, gameManager, req.Level)
+	case *model.AutoPlayRequest:
+		// 对局中开始或取消托管
+		if room.GameState != model.GameStatePlaying {
+			return false, model.NewGameError(model.ErrCodeInvalidState, "只有对局进行中可以托管")
+		}
+		setAutoPlay(player, room, gameManager, req.Enabled, "manual")
+	case *model.LeaveRoomRequest:
+		// 玩家主动离开房间
+		handlePlayerLeave(player, room, gameManager)
+		return true, nil
+	}
+	return false, nil
+}
+
+// 向玩家发送房间信息，对局进行中（刷新页面或断线重连）再补发该玩家视角的完整状态
+func sendRoomState(player *model.Player, room *model.Room) {
+	room.SendDirect(player, model.Message{
+		Type: model.MsgRoomInfo,
+		Data: room.GetRoomInfo(),
+	})
+
+	if room.GameState == model.GameStatePlaying {
+		room.SendDirect(player, model.Message{
+			Type: model.MsgGameSnapshot,
+			Data: room.SeatView(player.ID),
+		})
+	}
+}
+
+// 向玩家发送错误消息，调用方需持有房间锁
+func sendError(player *model.Player, room *model.Room, reqType string, err error) {
+	room.Transport.Deliver(player.ID, errorMessage(reqType, err))
+}
+
+// errorMessage 请求出错时回复的消息，非 GameError 的错误不向客户端暴露细节
+func errorMessage(reqType string, err error) model.Message {
+	data := model.ErrorData{
+		Code:        model.ErrCodeInternal,
+		Message:     "服务器内部错误",
+		RequestType: reqType,
+	}
+	var gameErr *model.GameError
+	if errors.As(err, &gameErr) {
+		data.Code = gameErr.Code
+		data.Message = gameErr.Message
+	} else {
+		config.GetZapLogger().Error("处理请求失败: " + err.Error())
+	}
+
+	return model.Message{
+		Type: model.MsgError,
+		Data: data,
+	}
+}
+
+// 处理玩家掉线：心跳超时或连接异常关闭，调用方需持有房间锁
+// 对局进行中由机器人托管并保留座位等待重连，超过宽限时间仍未重连才按离开处理；未开局时直接离开
+// 停服时的断开同样保留座位但不托管，房间在下次启动时恢复
+func handlePlayerDisconnect(player *model.Player, room *model.Room, gameManager *service.GameManager, err error) {
+	logger := config.GetZapLogger()
+
+	reason := "closed"
+	if transport.IsTimeout(err) {
+		reason = "timeout"
+	}
+	logger.Info("玩家 " + player.Name + " 掉线，原因: " + reason)
+
+	player.Connected = false
+	transport.For(room).Detach(player.ID)
+	if room.GetSpectator(player.ID) != nil {
+		holdSpectator(player, room)
+		return
+	}
+	broadcastConnectionStatus(room, player, reason)
+
+	if gameManager.Draining() {
+		holdSeat(player, room, gameManager)
+		return
+	}
+	if room.GameState != model.GameStatePlaying {
+		handlePlayerLeave(player, room, gameManager)
+		return
+	}
+	setAutoPlay(player, room, gameManager, true, "disconnected")
+	holdSeat(player, room, gameManager)
+}
+
+// HoldRestoredSeats 为服务重启后恢复的房间中的玩家保留座位，超时未重连按离开处理
+// 恢复的房间里所有玩家都是离线的，未开局的房间同样保留座位，让刷新页面的玩家回到原来的房间；
+// 机器人直接回到座位上，对局中的玩家由机器人托管到重连为止
+func HoldRestoredSeats(room *model.Room, gameManager *service.GameManager) {
+	room.Lock()
+	defer room.Unlock()
+
+	for _, player := range room.Players {
+		if player.Bot {
+			attachBot(player, room, gameManager)
+			continue
+		}
+		if room.GameState == model.GameStatePlaying {
+			setAutoPlay(player, room, gameManager, true, "disconnected")
+		}
+		holdSeat(player, room, gameManager)
+	}
+}
+
+// holdSeat 为离线玩家保留座位，调用方需持有房间锁
+func holdSeat(player *model.Player, room *model.Room, gameManager *service.GameManager) {
+	logger := config.GetZapLogger()
+
+	grace := viper.GetDuration("game.reconnectGrace")
+	logger.Info("为玩家 " + player.Name + " 保留座位 " + grace.String())
+	transport.For(room).Hold(player.ID, grace, func() {
+		room.Lock()
+		defer room.Unlock()
+
+		// 计时器触发时玩家可能刚好重连
+		if player.Connected || room.GetPlayer(player.ID) == nil {
+			return
+		}
+		// 对局中离开会打断这一局，继续托管到本局结束之后再离开
+		if room.GameState == model.GameStatePlaying && player.AutoPlay {
+			logger.Info("玩家 " + player.Name + " 重连超时，托管到本局结束")
+			holdSeat(player, room, gameManager)
+			return
+		}
+		logger.Info("玩家 " + player.Name + " 重连超时")
+		handlePlayerLeave(player, room, gameManager)
+	})
+}
+
+// 广播玩家的在线状态变化
+func broadcastConnectionStatus(room *model.Room, player *model.Player, reason string) {
+	room.BroadcastExcept(model.Message{
+		Type: model.MsgConnectionStatus,
+		Data: model.ConnectionStatusData{
+			PlayerID:  player.ID,
+			Connected: player.Connected,
+			Reason:    reason,
+		},
+	}, player.ID)
+}
+
+// 处理玩家离开的函数，调用方需持有房间锁
+// 对局中离开不能直接让出座位，否则这一局的手牌和轮转都会乱掉：和掉线一样断开连接由机器人托管，本局结束之后再离开
 func handlePlayerLeave(player *model.Player, room *model.Room, gameManager *service.GameManager) {
 	logger := config.GetZapLogger()
 
@@ -155,39 +421,57 @@ func handlePlayerLeave(player *model.Player, room *model.Room, gameManager *serv
 		return
 	}
 
-	logger.Info("玩家断开连接: " + player.Name)
+	if room.GameState == model.GameStatePlaying && room.GetPlayer(player.ID) != nil {
+		logger.Info("玩家 " + player.Name + " 在对局中离开，托管到本局结束")
+		// 先取消登记，读协程随后退出时不会再按掉线处理
+		player.Connected = false
+		if conn := transport.For(room).Detach(player.ID); conn != nil {
+			conn.Close()
+		}
+		broadcastConnectionStatus(room, player, "left")
+		setAutoPlay(player, room, gameManager, true, "left")
+		holdSeat(player, room, gameManager)
+		return
+	}
+
+	logger.Info("玩家离开房间: " + player.Name)
 
 	// 从房间中移除玩家
+	transport.For(room).Remove(player.ID)
+	player.AutoPlay = false
 	room.RemovePlayer(player.ID)
 
 	// 通知其他玩家
 	room.BroadcastAll(model.Message{
-		Type: "player_left",
-		Data: map[string]interface{}{
-			"playerID": player.ID,
-		},
+		Type: model.MsgPlayerLeft,
+		Data: model.PlayerLeftData{PlayerID: player.ID},
 	})
 
 	// 记录当前房间人数
 	logger.Info("当前玩家数量为：" + strconv.Itoa(len(room.Players)))
 
-	// 如果房间没有玩家了，删除房间
-	if len(room.Players) == 0 {
+	// 如果房间只剩机器人了，关闭机器人并删除房间
+	if len(room.Humans()) == 0 {
+		for _, p := range room.Recipients() {
+			transport.For(room).Remove(p.ID)
+		}
+		room.StopTimer()
 		logger.Info("房间 " + room.ID + " 已关闭")
 		gameManager.RemoveRoom(room.ID)
-	} else if room.Owner.ID == player.ID && len(room.Players) > 0 {
-		// 如果房主离开，选择新房主
-		newOwner := room.Players[0]
+		return
+	}
+	if room.Owner.ID == player.ID {
+		// 如果房主离开，从真人玩家中选择新房主
+		newOwner := room.Humans()[0]
 		room.SetOwner(newOwner)
 
 		// 广播新房主信息
 		room.BroadcastAll(model.Message{
-			Type: "new_owner",
-			Data: map[string]interface{}{
-				"ownerID": newOwner.ID,
-			},
+			Type: model.MsgNewOwner,
+			Data: model.NewOwnerData{OwnerID: newOwner.ID},
 		})
 	}
+	gameManager.SaveRoom(room)
 }
 
 // broadcastToRoom 向房间中的所有玩家广播消息
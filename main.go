@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"goMahjong/api"
 	"goMahjong/config"
+	"goMahjong/handler"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,11 +28,37 @@ func main() {
 	config.Init()
 	logger.Info("config init success")
 
-	// 初始化路由
-	api.Init(engine)
+	// 初始化路由，ctx 在停服排空之后结束
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gameManager := api.Init(ctx, engine)
 
 	// start server
 	port := viper.GetString("server.port")
-	logger.Info("server start at port " + port)
-	engine.Run(":" + port)
+	server := &http.Server{Addr: ":" + port, Handler: engine}
+	go func() {
+		logger.Info("server start at port " + port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("server start failed: " + err.Error())
+		}
+	}()
+
+	// 收到停止信号后排空房间再退出，再次收到信号时直接退出
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-signals.Done()
+	stop()
+	logger.Info("收到停止信号，开始停服")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), viper.GetDuration("server.shutdownTimeout"))
+	defer shutdownCancel()
+	handler.DrainRooms(shutdownCtx, gameManager, viper.GetDuration("server.shutdownCountdown"))
+	cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("关闭HTTP服务失败: " + err.Error())
+	}
+	if err := gameManager.Close(); err != nil {
+		logger.Error("关闭存储失败: " + err.Error())
+	}
+	logger.Info("server stopped")
 }
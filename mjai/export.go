@@ -0,0 +1,145 @@
+package mjai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goMahjong/model"
+	"goMahjong/rules"
+	"io"
+)
+
+// Export 把一局回放转换成MJAI事件，一个回放对应一局
+func Export(replay *model.Replay) ([]Event, error) {
+	if len(replay.Events) == 0 || replay.Events[0].Type != model.EventDeal {
+		return nil, errors.New("回放没有以发牌开始")
+	}
+
+	seats := make(map[string]int)
+	names := make([]string, 0, len(replay.Seats))
+	for i, seat := range replay.Seats {
+		seats[seat.ID] = i
+		names = append(names, seat.Name)
+	}
+	seat := func(id string) *int {
+		i, ok := seats[id]
+		if !ok {
+			return nil
+		}
+		return intp(i)
+	}
+
+	// 胡牌事件带上结算的输赢，结算事件本身不再单独导出
+	var deltas []int
+	for _, e := range replay.Events {
+		if e.Type == model.EventSettlement {
+			deltas = make([]int, len(replay.Seats))
+			for id, delta := range e.Settlement.Deltas {
+				if i, ok := seats[id]; ok {
+					deltas[i] = delta
+				}
+			}
+		}
+	}
+
+	out := []Event{{Type: TypeStartGame, Names: names}}
+	var last model.Event
+	won := false
+	for _, e := range replay.Events {
+		switch e.Type {
+		case model.EventDeal:
+			// 起手牌张数不对的回放无法按MJAI的格式导出
+			for _, s := range e.Deal.Seats {
+				want := 13
+				if s.ID == e.Deal.Dealer {
+					want = 14
+				}
+				if n := len(e.Deal.Hands[s.ID]); n != want {
+					return nil, fmt.Errorf("%s 的起手牌有 %d 张，应该是 %d 张", s.Name, n, want)
+				}
+			}
+			dealer := seats[e.Deal.Dealer]
+			start := Event{
+				Type:       TypeStartKyoku,
+				Bakaze:     "E",
+				Kyoku:      intp(dealer + 1),
+				Honba:      intp(0),
+				Kyotaku:    intp(0),
+				Oya:        intp(dealer),
+				DoraMarker: "?",
+				Scores:     make([]int, 0, len(e.Deal.Seats)),
+				Tehais:     make([][]string, 0, len(e.Deal.Seats)),
+			}
+			for _, s := range e.Deal.Seats {
+				start.Scores = append(start.Scores, s.Score)
+				start.Tehais = append(start.Tehais, tiles(e.Deal.Hands[s.ID][:13]))
+			}
+			out = append(out, start)
+			// 庄家的第14张牌按摸牌导出
+			out = append(out, Event{Type: TypeTsumo, Actor: intp(dealer), Pai: Tile(e.Deal.Hands[e.Deal.Dealer][13])})
+
+		case model.EventDingque:
+			out = append(out, Event{Type: TypeDingque, Actor: seat(e.PlayerID), Suit: toMJAISuit[e.Suit]})
+
+		case model.EventDraw:
+			out = append(out, Event{Type: TypeTsumo, Actor: seat(e.PlayerID), Pai: Tile(e.Tile)})
+
+		case model.EventDiscard:
+			tsumogiri := last.Type == model.EventDraw && last.PlayerID == e.PlayerID && last.Tile == e.Tile
+			out = append(out, Event{Type: TypeDahai, Actor: seat(e.PlayerID), Pai: Tile(e.Tile), Tsumogiri: boolp(tsumogiri)})
+
+		case model.EventClaim:
+			out = append(out, Event{Type: TypePon, Actor: seat(e.PlayerID), Target: seat(e.From), Pai: Tile(e.Tile), Consumed: repeat(Tile(e.Tile), 2)})
+
+		case model.EventGang:
+			pai := Tile(e.Tile)
+			switch e.Kind {
+			case rules.MeldMingGang:
+				out = append(out, Event{Type: TypeDaiminkan, Actor: seat(e.PlayerID), Target: seat(e.From), Pai: pai, Consumed: repeat(pai, 3)})
+			case rules.MeldAnGang:
+				out = append(out, Event{Type: TypeAnkan, Actor: seat(e.PlayerID), Consumed: repeat(pai, 4)})
+			case rules.MeldBuGang:
+				out = append(out, Event{Type: TypeKakan, Actor: seat(e.PlayerID), Pai: pai, Consumed: repeat(pai, 3)})
+			}
+
+		case model.EventWin:
+			target := seat(e.PlayerID)
+			if e.From != "" {
+				target = seat(e.From)
+			}
+			won = true
+			out = append(out, Event{
+				Type:     TypeHora,
+				Actor:    seat(e.PlayerID),
+				Target:   target,
+				Pai:      Tile(e.Tile),
+				Deltas:   deltas,
+				Fan:      intp(e.Win.Fan),
+				Patterns: e.Win.Patterns,
+			})
+
+		case model.EventSettlement:
+			if !won {
+				out = append(out, Event{Type: TypeRyukyoku, Deltas: deltas})
+			}
+			out = append(out, Event{Type: TypeEndKyoku})
+		}
+		last = e
+	}
+	return append(out, Event{Type: TypeEndGame}), nil
+}
+
+// Write 把回放以MJAI格式写出，每行一个事件
+func Write(w io.Writer, replay *model.Replay) error {
+	events, err := Export(replay)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	for _, e := range events {
+		if err := encoder.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package mjai
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goMahjong/model"
+	"goMahjong/rules"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+// Read 读取MJAI日志，每一局转换成一个回放
+func Read(r io.Reader) ([]*model.Replay, error) {
+	events := make([]Event, 0)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(text), &e); err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", line, err)
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return Import(events)
+}
+
+// Import 把MJAI事件转换成回放，每个 start_kyoku 到 end_kyoku 是一局
+// 导入的回放没有随机种子，牌墙中没有被摸到的部分按顺序补齐
+func Import(events []Event) ([]*model.Replay, error) {
+	var names []string
+	replays := make([]*model.Replay, 0)
+	for i := 0; i < len(events); i++ {
+		switch events[i].Type {
+		case TypeStartGame:
+			names = events[i].Names
+		case TypeStartKyoku:
+			end := i + 1
+			for end < len(events) && events[end].Type != TypeEndKyoku {
+				end++
+			}
+			if end == len(events) {
+				return nil, fmt.Errorf("第 %d 局没有 end_kyoku", len(replays)+1)
+			}
+			replay, err := importKyoku(names, events[i:end])
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 局: %w", len(replays)+1, err)
+			}
+			replays = append(replays, replay)
+			i = end
+		}
+	}
+	if len(replays) == 0 {
+		return nil, errors.New("日志中没有对局")
+	}
+	return replays, nil
+}
+
+// kyoku 转换一局时的状态
+type kyoku struct {
+	seats  []model.SeatRecord
+	events []model.Event
+	drawn  []string // 按顺序摸到的牌，用来还原牌墙
+}
+
+// seat 把座位序号转换成玩家ID
+func (k *kyoku) seat(i *int) (string, error) {
+	if i == nil || *i < 0 || *i >= len(k.seats) {
+		return "", errors.New("座位序号无效")
+	}
+	return k.seats[*i].ID, nil
+}
+
+// importKyoku 转换一局，events 从 start_kyoku 开始，不包括 end_kyoku
+func importKyoku(names []string, events []Event) (*model.Replay, error) {
+	start := events[0]
+	if start.Oya == nil || *start.Oya < 0 || *start.Oya >= len(start.Tehais) {
+		return nil, errors.New("start_kyoku 缺少庄家")
+	}
+
+	k := &kyoku{}
+	deal := &model.DealDetail{Hands: make(map[string][]string)}
+	for i, tehai := range start.Tehais {
+		seat := model.SeatRecord{ID: fmt.Sprintf("seat%d", i), Name: fmt.Sprintf("玩家%d", i+1)}
+		if i < len(names) {
+			seat.Name = names[i]
+		}
+		if i < len(start.Scores) {
+			seat.Score = start.Scores[i]
+		}
+		hand, err := parseTiles(tehai)
+		if err != nil {
+			return nil, err
+		}
+		k.seats = append(k.seats, seat)
+		deal.Hands[seat.ID] = hand
+	}
+	deal.Seats = k.seats
+	deal.Dealer = k.seats[*start.Oya].ID
+
+	// 庄家出牌前的摸牌是起手的第14张
+	rest := events[1:]
+	for j, e := range rest {
+		if e.Type == TypeDahai {
+			break
+		}
+		if e.Type == TypeTsumo && e.Actor != nil && *e.Actor == *start.Oya {
+			tile, err := ParseTile(e.Pai)
+			if err != nil {
+				return nil, err
+			}
+			deal.Hands[deal.Dealer] = append(deal.Hands[deal.Dealer], tile)
+			rest = append(append([]Event(nil), rest[:j]...), rest[j+1:]...)
+			break
+		}
+	}
+
+	for _, e := range rest {
+		if err := k.convert(e); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Type, err)
+		}
+	}
+
+	wall, err := restoreWall(deal.Hands, k.drawn)
+	if err != nil {
+		return nil, err
+	}
+	deal.Wall = wall
+	k.events = append([]model.Event{{Type: model.EventDeal, Deal: deal}}, k.events...)
+
+	replay := &model.Replay{
+		Version: model.ReplayVersion,
+		ID:      uuid.New().String(),
+		Ruleset: model.Ruleset{
+			Name:      model.RulesetSichuanSingleWin,
+			BaseScore: viper.GetInt("game.baseScore"),
+			MaxFan:    viper.GetInt("game.maxFan"),
+		},
+		Seats:     k.seats,
+		Events:    k.events,
+		CreatedAt: time.Now(),
+	}
+
+	// 按规则重放一遍：起手牌张数、定缺、胡牌、番数和结算都要与对局引擎算出的一致
+	room, err := replay.Verify()
+	if err != nil {
+		return nil, err
+	}
+	replay.FinalScores = make(map[string]int)
+	for _, p := range room.Players {
+		replay.FinalScores[p.ID] = p.Score
+	}
+	return replay, nil
+}
+
+// convert 把一个MJAI事件转换成对局事件
+func (k *kyoku) convert(e Event) error {
+	switch e.Type {
+	case TypeRyukyoku:
+		return k.settle(e.Deltas)
+	case TypeStartGame, TypeEndGame:
+		return nil
+	}
+
+	actor, err := k.seat(e.Actor)
+	if err != nil {
+		return err
+	}
+	var tile string
+	if e.Type == TypeAnkan {
+		if len(e.Consumed) != 4 {
+			return errors.New("暗杠需要四张牌")
+		}
+		tile, err = ParseTile(e.Consumed[0])
+	} else if e.Type != TypeDingque {
+		tile, err = ParseTile(e.Pai)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch e.Type {
+	case TypeDingque:
+		suit, ok := fromMJAI[e.Suit]
+		if !ok {
+			return errors.New("定缺的花色无效: " + e.Suit)
+		}
+		k.events = append(k.events, model.Event{Type: model.EventDingque, PlayerID: actor, Suit: suit})
+
+	case TypeTsumo:
+		k.drawn = append(k.drawn, tile)
+		k.events = append(k.events, model.Event{Type: model.EventDraw, PlayerID: actor, Tile: tile})
+
+	case TypeDahai:
+		k.events = append(k.events, model.Event{Type: model.EventDiscard, PlayerID: actor, Tile: tile})
+
+	case TypePon, TypeDaiminkan:
+		target, err := k.seat(e.Target)
+		if err != nil {
+			return err
+		}
+		event := model.Event{Type: model.EventClaim, PlayerID: actor, Tile: tile, From: target}
+		if e.Type == TypeDaiminkan {
+			event.Type = model.EventGang
+			event.Kind = rules.MeldMingGang
+		}
+		k.events = append(k.events, event)
+
+	case TypeAnkan, TypeKakan:
+		kind := rules.MeldAnGang
+		if e.Type == TypeKakan {
+			kind = rules.MeldBuGang
+		}
+		k.events = append(k.events, model.Event{Type: model.EventGang, PlayerID: actor, Tile: tile, Kind: kind})
+
+	case TypeHora:
+		target, err := k.seat(e.Target)
+		if err != nil {
+			return err
+		}
+		win := &model.WinDetail{SelfDrawn: target == actor, Patterns: e.Patterns}
+		if e.Fan != nil {
+			win.Fan = *e.Fan
+		}
+		if win.Patterns == nil {
+			win.Patterns = make([]string, 0)
+		}
+		event := model.Event{Type: model.EventWin, PlayerID: actor, Tile: tile, Win: win}
+		if !win.SelfDrawn {
+			event.From = target
+		}
+		k.events = append(k.events, event)
+		return k.settle(e.Deltas)
+
+	default:
+		return errors.New("四川麻将不支持这种事件")
+	}
+	return nil
+}
+
+// settle 添加结算事件，MJAI的输赢按座位顺序排列
+func (k *kyoku) settle(deltas []int) error {
+	if len(deltas) != len(k.seats) {
+		return errors.New("deltas 与座位数不一致")
+	}
+	detail := &model.SettlementDetail{Deltas: make(map[string]int)}
+	for i, seat := range k.seats {
+		detail.Deltas[seat.ID] = deltas[i]
+	}
+	k.events = append(k.events, model.Event{Type: model.EventSettlement, Settlement: detail})
+	return nil
+}
+
+// restoreWall 起手牌之后的牌墙：先是按顺序摸到的牌，再按顺序补上没有出现过的牌
+func restoreWall(hands map[string][]string, drawn []string) ([]string, error) {
+	used := append([]string(nil), drawn...)
+	for _, hand := range hands {
+		used = append(used, hand...)
+	}
+
+	wall := append([]string(nil), drawn...)
+	for i := 0; i < rules.TileKinds; i++ {
+		tile := rules.TileAt(i)
+		n := rules.Count(used, tile)
+		if n > 4 {
+			return nil, errors.New("牌的张数超过4张: " + tile)
+		}
+		for ; n < 4; n++ {
+			wall = append(wall, tile)
+		}
+	}
+	return wall, nil
+}
+
+func parseTiles(list []string) ([]string, error) {
+	result := make([]string, 0, len(list))
+	for _, pai := range list {
+		tile, err := ParseTile(pai)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tile)
+	}
+	return result, nil
+}
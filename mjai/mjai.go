@@ -0,0 +1,105 @@
+// Package mjai 在回放和MJAI日志之间转换
+//
+// MJAI日志每行一个JSON事件，社区的牌谱分析工具大多能读取这种格式。
+// 四川麻将没有吃、立直和宝牌，导出时 dora_marker 固定为 "?"；
+// 定缺用自定义的 dingque 事件表示，胡牌事件额外带上 fan 和 patterns。
+// 天凤格式只能表达日本麻将的规则（役、宝牌、供托），本项目只有四川麻将房间，因此不提供。
+package mjai
+
+import (
+	"errors"
+	"goMahjong/rules"
+	"strings"
+)
+
+// 事件类型
+const (
+	TypeStartGame  = "start_game"
+	TypeStartKyoku = "start_kyoku"
+	TypeDingque    = "dingque" // 自定义：定缺
+	TypeTsumo      = "tsumo"
+	TypeDahai      = "dahai"
+	TypePon        = "pon"
+	TypeDaiminkan  = "daiminkan"
+	TypeAnkan      = "ankan"
+	TypeKakan      = "kakan"
+	TypeHora       = "hora"
+	TypeRyukyoku   = "ryukyoku"
+	TypeEndKyoku   = "end_kyoku"
+	TypeEndGame    = "end_game"
+)
+
+// Event MJAI日志中的一行
+// 座位序号、局数等0也有意义的字段用指针，没有出现时为nil
+type Event struct {
+	Type       string     `json:"type"`
+	Names      []string   `json:"names,omitempty"`
+	Bakaze     string     `json:"bakaze,omitempty"`
+	Kyoku      *int       `json:"kyoku,omitempty"`
+	Honba      *int       `json:"honba,omitempty"`
+	Kyotaku    *int       `json:"kyotaku,omitempty"`
+	Oya        *int       `json:"oya,omitempty"`
+	DoraMarker string     `json:"dora_marker,omitempty"`
+	Scores     []int      `json:"scores,omitempty"`
+	Tehais     [][]string `json:"tehais,omitempty"`
+	Actor      *int       `json:"actor,omitempty"`
+	Target     *int       `json:"target,omitempty"`
+	Pai        string     `json:"pai,omitempty"`
+	Consumed   []string   `json:"consumed,omitempty"`
+	Tsumogiri  *bool      `json:"tsumogiri,omitempty"`
+	Deltas     []int      `json:"deltas,omitempty"`
+	Suit       string     `json:"suit,omitempty"`     // 自定义：定缺的花色
+	Fan        *int       `json:"fan,omitempty"`      // 自定义：胡牌番数
+	Patterns   []string   `json:"patterns,omitempty"` // 自定义：番型
+}
+
+// MJAI的花色：万是m，筒是p，条是s
+var (
+	toMJAISuit = map[string]string{rules.SuitWan: "m", rules.SuitTong: "p", rules.SuitTiao: "s"}
+	fromMJAI   = map[string]string{"m": rules.SuitWan, "p": rules.SuitTong, "s": rules.SuitTiao}
+)
+
+// ErrUnsupportedTile 字牌等四川麻将中没有的牌
+var ErrUnsupportedTile = errors.New("四川麻将中没有这张牌")
+
+// Tile 把本项目的牌转换成MJAI的写法，如 "5w" -> "5m"
+func Tile(tile string) string {
+	return tile[:1] + toMJAISuit[rules.Suit(tile)]
+}
+
+// ParseTile 把MJAI的牌转换成本项目的写法，赤宝牌按普通的五处理
+func ParseTile(pai string) (string, error) {
+	pai = strings.TrimSuffix(pai, "r")
+	if len(pai) != 2 || pai[0] < '1' || pai[0] > '9' {
+		return "", ErrUnsupportedTile
+	}
+	suit, ok := fromMJAI[pai[1:]]
+	if !ok {
+		return "", ErrUnsupportedTile
+	}
+	return pai[:1] + suit, nil
+}
+
+func tiles(list []string) []string {
+	result := make([]string, 0, len(list))
+	for _, t := range list {
+		result = append(result, Tile(t))
+	}
+	return result
+}
+
+func repeat(pai string, n int) []string {
+	result := make([]string, n)
+	for i := range result {
+		result[i] = pai
+	}
+	return result
+}
+
+func intp(i int) *int {
+	return &i
+}
+
+func boolp(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,134 @@
+package mjai
+
+import (
+	"bytes"
+	"goMahjong/bot"
+	"goMahjong/model"
+	"goMahjong/simulate"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playHand 让机器人用种子发牌打完一局，返回回放；座位的难度各不相同，容易打出碰杠和不同的胡法
+func playHand(t *testing.T, seed int64, players int) *model.Replay {
+	levels := []string{bot.LevelEasy, bot.LevelNormal, bot.LevelHard, bot.LevelEasy}[:players]
+	replay, err := simulate.PlayReplay(seed, levels)
+	require.NoError(t, err, "种子 %d", seed)
+	return replay
+}
+
+func TestRoundTrip(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		replay := playHand(t, seed, 2+int(seed%3))
+
+		var exported bytes.Buffer
+		require.NoError(t, Write(&exported, replay))
+
+		imported, err := Read(bytes.NewReader(exported.Bytes()))
+		require.NoError(t, err, "种子 %d", seed)
+		require.Len(t, imported, 1)
+
+		// 导入后再导出得到同样的日志
+		var again bytes.Buffer
+		require.NoError(t, Write(&again, imported[0]))
+		assert.Equal(t, exported.String(), again.String(), "种子 %d", seed)
+
+		// 按座位比较最终分数
+		for i, seat := range replay.Seats {
+			assert.Equal(t, replay.FinalScores[seat.ID], imported[0].FinalScores[imported[0].Seats[i].ID])
+			assert.Equal(t, seat.Name, imported[0].Seats[i].Name)
+		}
+		assert.Equal(t, len(replay.Events), len(imported[0].Events))
+	}
+}
+
+func TestExportUsesMJAITiles(t *testing.T) {
+	replay := playHand(t, 7, 4)
+	events, err := Export(replay)
+	require.NoError(t, err)
+
+	assert.Equal(t, TypeStartGame, events[0].Type)
+	assert.Equal(t, TypeStartKyoku, events[1].Type)
+	assert.Len(t, events[1].Tehais, 4)
+	assert.Equal(t, TypeEndGame, events[len(events)-1].Type)
+	assert.Equal(t, TypeEndKyoku, events[len(events)-2].Type)
+	for _, tehai := range events[1].Tehais {
+		assert.Len(t, tehai, 13)
+		for _, pai := range tehai {
+			assert.Contains(t, "mps", pai[1:])
+		}
+	}
+
+	assert.Equal(t, "5m", Tile("5w"))
+	assert.Equal(t, "5s", Tile("5t"))
+	tile, err := ParseTile("5pr")
+	require.NoError(t, err)
+	assert.Equal(t, "5p", tile)
+	_, err = ParseTile("E")
+	assert.ErrorIs(t, err, ErrUnsupportedTile)
+}
+
+func TestImportRejectsRiichiLogs(t *testing.T) {
+	log := strings.Join([]string{
+		`{"type":"start_game","names":["a","b","c","d"]}`,
+		`{"type":"start_kyoku","bakaze":"E","kyoku":1,"honba":0,"kyotaku":0,"oya":0,"dora_marker":"5s",` +
+			`"tehais":[["1m","2m","3m","4m","5m","6m","7m","8m","9m","1p","2p","3p","E"],` +
+			`["1s","2s","3s","4s","5s","6s","7s","8s","9s","1p","2p","3p","4p"],` +
+			`["1s","2s","3s","4s","5s","6s","7s","8s","9s","1p","2p","3p","4p"],` +
+			`["1s","2s","3s","4s","5s","6s","7s","8s","9s","1p","2p","3p","4p"]]}`,
+		`{"type":"end_kyoku"}`,
+	}, "\n")
+	_, err := Read(strings.NewReader(log))
+	assert.ErrorIs(t, err, ErrUnsupportedTile)
+
+	_, err = Read(strings.NewReader(`{"type":"start_game","names":[]}`))
+	assert.Error(t, err)
+}
+
+// 庄家起手就自摸的一局，用来构造各种不符合规则的日志
+func heavenlyWinLog(replace ...string) string {
+	log := strings.Join([]string{
+		`{"type":"start_game","names":["庄家","闲家"]}`,
+		`{"type":"start_kyoku","bakaze":"E","kyoku":1,"honba":0,"kyotaku":0,"oya":0,"dora_marker":"?","scores":[0,0],` +
+			`"tehais":[["1m","2m","3m","4m","5m","6m","7m","8m","9m","1p","2p","3p","5p"],` +
+			`["1s","2s","3s","4s","5s","6s","7s","8s","9s","6p","7p","8p","9p"]]}`,
+		`{"type":"tsumo","actor":0,"pai":"5p"}`,
+		`{"type":"dingque","actor":0,"suit":"s"}`,
+		`{"type":"dingque","actor":1,"suit":"m"}`,
+		`{"type":"hora","actor":0,"target":0,"pai":"5p","deltas":[2,-2],"fan":1,"patterns":["平胡","自摸"]}`,
+		`{"type":"end_kyoku"}`,
+	}, "\n")
+	return strings.NewReplacer(replace...).Replace(log)
+}
+
+// 导入时按规则重放，只是牌的去向对得上还不够
+func TestImportChecksTheRules(t *testing.T) {
+	_, err := Read(strings.NewReader(heavenlyWinLog()))
+	require.NoError(t, err)
+
+	for name, log := range map[string]string{
+		"起手牌只有一张": heavenlyWinLog(`["1m","2m","3m","4m","5m","6m","7m","8m","9m","1p","2p","3p","5p"]`, `["5p"]`, `[2,-2]`, `[1000,-1000]`),
+		"输赢与番数不符": heavenlyWinLog(`[2,-2]`, `[1000,-1000]`),
+		"番数不对":    heavenlyWinLog(`"fan":1`, `"fan":3`),
+		"没有胡牌":    heavenlyWinLog(`"tsumo","actor":0,"pai":"5p"`, `"tsumo","actor":0,"pai":"9p"`, `"pai":"5p","deltas"`, `"pai":"9p","deltas"`),
+		"胡了缺门的牌":  heavenlyWinLog(`"actor":0,"suit":"s"`, `"actor":0,"suit":"p"`),
+		"定缺之前出牌":  heavenlyWinLog(`{"type":"dingque","actor":0`, `{"type":"dahai","actor":0,"pai":"5p"}`+"\n"+`{"type":"dingque","actor":0`),
+	} {
+		_, err := Read(strings.NewReader(log))
+		assert.Error(t, err, name)
+	}
+}
+
+// 起手牌张数不对的回放导出时返回错误
+func TestExportRejectsMalformedHands(t *testing.T) {
+	replay := playHand(t, 3, 2)
+	deal := *replay.Events[0].Deal
+	deal.Hands = map[string][]string{deal.Seats[0].ID: {"5p"}, deal.Seats[1].ID: {"5p"}}
+	replay.Events = append([]model.Event{{Type: model.EventDeal, Deal: &deal}}, replay.Events[1:]...)
+
+	_, err := Export(replay)
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+)
+
+// 消息方向
+const (
+	DirectionServer = "server" // 服务端 -> 客户端
+	DirectionClient = "client" // 客户端 -> 服务端
+)
+
+// MessageSpec 描述一种WebSocket消息
+type MessageSpec struct {
+	Type        string
+	Direction   string
+	Description string
+	Payload     interface{} // 消息Data的结构，用于生成JSON Schema
+}
+
+// Catalogue 协议中所有消息类型的唯一来源
+// 客户端请求的解析、/api/protocol 文档和契约测试都以这里为准
+var Catalogue = []MessageSpec{
+	// 服务端 -> 客户端
+	{MsgWelcome, DirectionServer, "连接建立后告知协商好的协议版本", WelcomeData{}},
+	{MsgError, DirectionServer, "请求处理失败，只发给请求者", ErrorData{}},
+	{MsgRoomInfo, DirectionServer, "房间信息，连接建立后发送", RoomInfoData{}},
+	{MsgPlayerJoined, DirectionServer, "有玩家加入房间", PlayerJoinedData{}},
+	{MsgPlayerLeft, DirectionServer, "有玩家离开房间", PlayerLeftData{}},
+	{MsgNewOwner, DirectionServer, "房主变更", NewOwnerData{}},
+	{MsgConnectionStatus, DirectionServer, "玩家在线状态变化", ConnectionStatusData{}},
+	{MsgAutoPlay, DirectionServer, "玩家开始或取消托管，对局中掉线的玩家自动托管，重连后取消", AutoPlayData{}},
+	{MsgChat, DirectionServer, "聊天消息，spectator 表示观战者发的", ChatData{}},
+	{MsgSpectators, DirectionServer, "观战人数变化", SpectatorsData{}},
+	{MsgCoachView, DirectionServer, "只发给教练：延迟一段时间后公开的所有人手牌和定缺", CoachViewData{}},
+	{MsgGameStarted, DirectionServer, "游戏开始，附带收件人自己的手牌", GameStartedData{}},
+	{MsgGameSnapshot, DirectionServer, "刷新或重连后某个玩家视角的完整对局状态", GameSnapshotData{}},
+	{MsgTilePlayed, DirectionServer, "有玩家出牌", TilePlayedData{}},
+	{MsgNewTile, DirectionServer, "有玩家摸牌，只有本人能看到牌面", NewTileData{}},
+	{MsgTurnChanged, DirectionServer, "轮到某个玩家", TurnChangedData{}},
+	{MsgDingque, DirectionServer, "所有人定缺完成，公布各家缺的花色", DingqueData{}},
+	{MsgClaimWindow, DirectionServer, "提示能碰、杠、胡的玩家做出选择，只发给这些玩家，不占用序号", ClaimWindowData{}},
+	{MsgMeld, DirectionServer, "有玩家碰或杠", MeldData{}},
+	{MsgGameOver, DirectionServer, "本局结束，附带结算、所有人的手牌和回放ID", GameOverData{}},
+	{MsgServerShutdown, DirectionServer, "服务器即将停止，不能再开始新的一局，倒计时结束后断开连接", ServerShutdownData{}},
+	{MsgRoomClosed, DirectionServer, "房间被清理，随后断开连接，客户端不应重连", RoomClosedData{}},
+
+	// 客户端 -> 服务端
+	{ReqChat, DirectionClient, "发送聊天消息", ChatRequest{}},
+	{ReqStartGame, DirectionClient, "房主开始游戏", StartGameRequest{}},
+	{ReqPlayTile, DirectionClient, "出牌", PlayTileRequest{}},
+	{ReqDingque, DirectionClient, "定缺，开局后每人选一门不要的花色", DingqueRequest{}},
+	{ReqAction, DirectionClient, "碰、杠、胡或过", ActionRequest{}},
+	{ReqLeaveRoom, DirectionClient, "离开房间", LeaveRoomRequest{}},
+	{ReqAutoPlay, DirectionClient, "对局中开始或取消托管", AutoPlayRequest{}},
+	{ReqAddBot, DirectionClient, "房主在开局前添加一个机器人，level 为 easy、normal、hard 或 expert", AddBotRequest{}},
+	{ReqResync, DirectionClient, "发现序号断档后请求补发afterSeq之后的消息", ResyncRequest{}},
+}
+
+// FindMessageSpec 按方向和类型查找消息定义
+func FindMessageSpec(direction, msgType string) (MessageSpec, bool) {
+	for _, spec := range Catalogue {
+		if spec.Direction == direction && spec.Type == msgType {
+			return spec, true
+		}
+	}
+	return MessageSpec{}, false
+}
+
+// ProtocolDocument 生成机器可读的协议文档，每种消息的Data用JSON Schema描述
+func ProtocolDocument() map[string]interface{} {
+	messages := make([]map[string]interface{}, 0, len(Catalogue))
+	for _, spec := range Catalogue {
+		messages = append(messages, map[string]interface{}{
+			"type":        spec.Type,
+			"direction":   spec.Direction,
+			"description": spec.Description,
+			"schema":      JSONSchema(reflect.TypeOf(spec.Payload)),
+		})
+	}
+
+	return map[string]interface{}{
+		"$schema":            "https://json-schema.org/draft/2020-12/schema",
+		"protocolVersion":    ProtocolVersion,
+		"minProtocolVersion": MinProtocolVersion,
+		"envelope":           JSONSchema(reflect.TypeOf(Message{})),
+		"messages":           messages,
+	}
+}
+
+// JSONSchema 根据Go类型和json标签生成JSON Schema
+// 没有omitempty的字段视为必填，指针和map允许为null
+func JSONSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := JSONSchema(t.Elem())
+		schema["type"] = []interface{}{schema["type"], "null"}
+		return schema
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		// nil切片会被编码为null
+		return map[string]interface{}{"type": []interface{}{"array", "null"}, "items": JSONSchema(t.Elem())}
+	case reflect.Array:
+		return map[string]interface{}{"type": "array", "items": JSONSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 []interface{}{"object", "null"},
+			"additionalProperties": JSONSchema(t.Elem()),
+		}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		required := make([]string, 0)
+		collectFields(t, properties, &required)
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	// interface{} 等任意类型
+	return map[string]interface{}{}
+}
+
+// 收集结构体字段，匿名嵌入的结构体字段会被展开
+func collectFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			collectFields(field.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = JSONSchema(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
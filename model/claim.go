@@ -0,0 +1,127 @@
+package model
+
+import "goMahjong/rules"
+
+// claimWindow 有人出牌后等待其他玩家响应
+// 窗口由出牌事件根据当时的手牌算出，玩家的选择不记入事件，重建房间后需要重新选择
+type claimWindow struct {
+	tile      string
+	from      string              // 出牌的玩家ID
+	options   map[string][]string // 玩家ID -> 可以执行的动作
+	responses map[string]string   // 玩家ID -> 选择的动作
+}
+
+// openClaimWindow 计算其他玩家对这张牌能做的动作，没有人能操作时直接进入摸牌阶段
+func (r *Room) openClaimWindow(discarder *Player, tile string) {
+	options := make(map[string][]string)
+	for _, p := range r.Players {
+		if p.ID == discarder.ID || p.Missing == rules.Suit(tile) {
+			continue
+		}
+
+		actions := make([]string, 0)
+		hand := append(append([]string(nil), p.Tiles...), tile)
+		if rules.CanWin(hand, p.Missing) {
+			actions = append(actions, ActionHu)
+		}
+		n := rules.Count(p.Tiles, tile)
+		if n >= 3 && len(r.Tiles) > 0 {
+			actions = append(actions, ActionGang)
+		}
+		if n >= 2 {
+			actions = append(actions, ActionPeng)
+		}
+		if len(actions) > 0 {
+			options[p.ID] = actions
+		}
+	}
+
+	if len(options) == 0 {
+		r.claim = nil
+		r.Phase = PhaseDraw
+		return
+	}
+	r.claim = &claimWindow{
+		tile:      tile,
+		from:      discarder.ID,
+		options:   options,
+		responses: make(map[string]string),
+	}
+	r.Phase = PhaseClaim
+}
+
+// pendingClaimOptions 玩家在当前窗口中还没有做出选择的动作
+func (r *Room) pendingClaimOptions(playerID string) []string {
+	if r.claim == nil {
+		return nil
+	}
+	if _, responded := r.claim.responses[playerID]; responded {
+		return nil
+	}
+	return r.claim.options[playerID]
+}
+
+// claimPrompt 发给还没有做出选择的玩家的碰杠胡提示，玩家不能碰杠胡时返回false
+func (r *Room) claimPrompt(playerID string) (Message, bool) {
+	options := r.pendingClaimOptions(playerID)
+	if len(options) == 0 {
+		return Message{}, false
+	}
+	return Message{Type: MsgClaimWindow, Data: ClaimWindowData{
+		Tile:    r.claim.tile,
+		From:    r.claim.from,
+		Options: append(append([]string(nil), options...), ActionPass),
+		Seconds: seconds(r.timeLeft()),
+	}}, true
+}
+
+// turnOptions 当前玩家在出牌前可以执行的动作：刚摸牌后能胡就可以自摸，手里有四张或碰过又摸到就可以杠
+func (r *Room) turnOptions(p *Player) []string {
+	if r.Phase != PhaseDiscard || r.Players[r.CurrentPlayerIndex].ID != p.ID {
+		return nil
+	}
+
+	actions := make([]string, 0)
+	if r.lastDrawn != "" && rules.CanWin(p.Tiles, p.Missing) {
+		actions = append(actions, ActionHu)
+	}
+	if len(r.Tiles) > 0 && len(r.gangTiles(p)) > 0 {
+		actions = append(actions, ActionGang)
+	}
+	return actions
+}
+
+// gangTiles 返回玩家在自己回合可以暗杠或补杠的牌，缺门的牌不能杠
+func (r *Room) gangTiles(p *Player) []string {
+	tiles := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, t := range p.Tiles {
+		if seen[t] || rules.Suit(t) == p.Missing {
+			continue
+		}
+		seen[t] = true
+		if rules.Count(p.Tiles, t) == 4 || p.findPeng(t) != nil {
+			tiles = append(tiles, t)
+		}
+	}
+	return tiles
+}
+
+// claimOrder 从出牌者的下家开始的座位顺序，多人同时胡同一张牌时按这个顺序截胡
+func (r *Room) claimOrder(fromID string) []*Player {
+	start := r.seatIndex(fromID)
+	order := make([]*Player, 0, len(r.Players)-1)
+	for i := 1; i < len(r.Players); i++ {
+		order = append(order, r.Players[(start+i)%len(r.Players)])
+	}
+	return order
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
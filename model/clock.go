@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Clock 房间计时使用的时钟，由调用方注入：服务端使用系统时钟，测试可以换成手动推进的时钟
+// 房间的 Clock 为空时不计时，超时不会触发，也不发送教练视角，模拟对局和回放重建都是这样运行的
+type Clock interface {
+	Now() time.Time
+	// AfterFunc 在 d 之后调用 f，f 在自己的协程中运行，需要自己给房间加锁
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer 由 Clock.AfterFunc 创建的计时器
+type Timer interface {
+	Stop() bool
+}
+
+// Timing 房间的时间设置，0 表示对应的阶段不计时
+type Timing struct {
+	Dingque    time.Duration // 定缺的时间
+	Turn       time.Duration // 出牌的基础时间
+	Claim      time.Duration // 响应碰杠胡的时间
+	TimeBank   time.Duration // 每局的时间银行
+	CoachDelay time.Duration // 教练视角延迟公开手牌的时间，0 表示不允许教练观战
+}
@@ -0,0 +1,16 @@
+package model
+
+// Codec 负责消息在线上的编码格式，每个连接在握手时通过WebSocket子协议选定一种
+// 房间只产生与编码无关的Message，由连接在发送时按自己的Codec序列化
+type Codec interface {
+	// Subprotocol 对应的WebSocket子协议名
+	Subprotocol() string
+	// FrameType WebSocket帧类型，文本或二进制
+	FrameType() int
+	// Encode 编码一条服务端消息
+	Encode(message Message) ([]byte, error)
+	// Decode 解码客户端消息的外层，Data保持原始负载
+	Decode(data []byte) (Envelope, error)
+	// DecodePayload 把Envelope.Data解析到具体的请求结构
+	DecodePayload(data []byte, v interface{}) error
+}
@@ -0,0 +1,65 @@
+package model
+
+// Command 玩家对房间的一次对局操作，和客户端的请求一一对应
+// 引擎不关心操作来自WebSocket、机器人、测试还是命令行
+type Command interface {
+	execute(r *Room) error
+}
+
+// StartGameCommand 房主开始游戏
+type StartGameCommand struct {
+	PlayerID string
+}
+
+// DingqueCommand 定缺
+type DingqueCommand struct {
+	PlayerID string
+	Suit     string
+}
+
+// PlayTileCommand 出牌
+type PlayTileCommand struct {
+	PlayerID string
+	Tile     string
+}
+
+// ActionCommand 碰、杠、胡或过，自己摸牌后的杠需要在 Tiles 中指定要杠的牌
+type ActionCommand struct {
+	PlayerID string
+	Action   string
+	Tiles    []string
+}
+
+func (c StartGameCommand) execute(r *Room) error {
+	return r.StartGameBy(c.PlayerID)
+}
+
+func (c DingqueCommand) execute(r *Room) error {
+	return r.HandleDingque(c.PlayerID, c.Suit)
+}
+
+func (c PlayTileCommand) execute(r *Room) error {
+	return r.HandlePlayTile(c.PlayerID, c.Tile)
+}
+
+func (c ActionCommand) execute(r *Room) error {
+	return r.HandlePlayerAction(c.PlayerID, c.Action, c.Tiles)
+}
+
+// Result 一次操作的结果
+type Result struct {
+	Events   []Event    // 这次操作记录的事件
+	Outbound []Outbound // 这次操作产生、还没有投递的消息，设置了传输层时为空
+}
+
+// Execute 执行一次操作，返回记录的事件和要发给每个玩家的消息，调用方需持有房间锁
+// 操作被拒绝时返回 GameError，房间状态和事件日志都不变
+func (r *Room) Execute(cmd Command) (Result, error) {
+	n := len(r.Events)
+	err := cmd.execute(r)
+	result := Result{
+		Events:   append([]Event(nil), r.Events[n:]...),
+		Outbound: r.TakeOutbound(),
+	}
+	return result, err
+}
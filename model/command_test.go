@@ -0,0 +1,77 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outboundFor 发给指定玩家的指定类型的消息
+func outboundFor(out []Outbound, playerID, msgType string) []Message {
+	messages := make([]Message, 0)
+	for _, o := range out {
+		if o.PlayerID == playerID && o.Message.Type == msgType {
+			messages = append(messages, o.Message)
+		}
+	}
+	return messages
+}
+
+// 没有传输层时，引擎只返回事件和按收件人生成的消息
+func TestExecuteReturnsEventsAndPerRecipientMessages(t *testing.T) {
+	room := newTestRoom(t, 3)
+	a, b := room.Players[0], room.Players[1]
+
+	// 被拒绝的操作没有事件也没有消息
+	result, err := room.Execute(StartGameCommand{PlayerID: b.ID})
+	assert.Error(t, err)
+	assert.Empty(t, result.Events)
+	assert.Empty(t, result.Outbound)
+
+	result, err = room.Execute(StartGameCommand{PlayerID: a.ID})
+	require.NoError(t, err)
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, EventDeal, result.Events[0].Type)
+
+	// 每个人的开局消息里只有自己的手牌，所有人共享同一个序号
+	for _, p := range room.Players {
+		started := outboundFor(result.Outbound, p.ID, MsgGameStarted)
+		require.Len(t, started, 1)
+		assert.Equal(t, p.Tiles, started[0].Data.(GameStartedData).Tiles)
+		assert.Equal(t, room.Seq(), started[0].Seq)
+	}
+	assert.Empty(t, room.TakeOutbound(), "Execute 取走了所有消息")
+}
+
+// 出牌之后下家摸牌，只有摸牌的人能看到是哪张
+func TestExecuteHidesTheDrawnTileFromOthers(t *testing.T) {
+	room := newTestRoom(t, 2)
+	a, b := room.Players[0], room.Players[1]
+	require.NoError(t, room.StartGameWithDeal(scriptedDeal(t, room, []string{
+		"9p 1w 1w 2w 2w 3w 3w 4w 4w 6w 6w 7w 8w 9w",
+		"1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 4p",
+	}, "5t")))
+	for _, p := range room.Players {
+		_, err := room.Execute(DingqueCommand{PlayerID: p.ID, Suit: fewestSuit(p.Tiles)})
+		require.NoError(t, err)
+	}
+
+	result, err := room.Execute(PlayTileCommand{PlayerID: a.ID, Tile: "9p"})
+	require.NoError(t, err)
+	types := make([]string, 0)
+	for _, e := range result.Events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{EventDiscard, EventDraw}, types)
+
+	for _, p := range room.Players {
+		drawn := outboundFor(result.Outbound, p.ID, MsgNewTile)
+		require.Len(t, drawn, 1)
+		if p == b {
+			assert.Equal(t, "5t", drawn[0].Data.(NewTileData).Tile)
+		} else {
+			assert.Empty(t, drawn[0].Data.(NewTileData).Tile)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package model
+
+import "github.com/spf13/viper"
+
+// Transport 把引擎产生的消息投递给玩家，由WebSocket等传输层实现，引擎只知道收件人的玩家ID
+// Deliver 在持有房间锁时调用，不能阻塞
+type Transport interface {
+	Deliver(playerID string, message Message)
+}
+
+// Outbound 一条待投递的消息，每条消息只发给一个玩家，按收件人的视角生成
+type Outbound struct {
+	PlayerID string
+	Message  Message
+}
+
+// delivery 一次房间广播的投递记录，保存每个收件人实际收到的消息，用于断档补发
+type delivery struct {
+	seq      uint64
+	messages map[string]Message // 玩家ID -> 该玩家收到的消息
+}
+
+// Seq 返回房间当前的消息序号
+func (r *Room) Seq() uint64 {
+	return r.seq
+}
+
+// BroadcastAll 向房间内所有玩家广播消息
+func (r *Room) BroadcastAll(message Message) {
+	r.publish(func(p *Player) (Message, bool) {
+		return message, true
+	})
+}
+
+// BroadcastExcept 向除了指定玩家外的所有玩家广播消息
+func (r *Room) BroadcastExcept(message Message, exceptPlayerID string) {
+	r.publish(func(p *Player) (Message, bool) {
+		return message, p.ID != exceptPlayerID
+	})
+}
+
+// BroadcastEach 向每个玩家发送按其视角生成的消息，所有人共享同一个序号
+func (r *Room) BroadcastEach(msgType string, dataFor func(p *Player) interface{}) {
+	r.publish(func(p *Player) (Message, bool) {
+		return Message{Type: msgType, Data: dataFor(p)}, true
+	})
+}
+
+// SendDirect 向单个玩家发送不占用序号的消息（连接信息、错误、状态快照等），
+// 序号为房间当前序号，表示消息反映的是到该序号为止的状态
+func (r *Room) SendDirect(player *Player, message Message) {
+	message.Seq = r.seq
+	r.emit(player, message)
+}
+
+// TakeOutbound 取走还没有投递的消息，没有设置传输层时房间产生的消息都留在这里
+func (r *Room) TakeOutbound() []Outbound {
+	out := r.outbox
+	r.outbox = nil
+	return out
+}
+
+// emit 把消息交给传输层，没有传输层时放进发件箱
+func (r *Room) emit(player *Player, message Message) {
+	if r.Transport != nil {
+		r.Transport.Deliver(player.ID, message)
+		return
+	}
+	r.outbox = append(r.outbox, Outbound{PlayerID: player.ID, Message: message})
+}
+
+// Replay 返回玩家在afterSeq之后应收到的消息
+// 记录已经不够久远时返回false，调用方应改为发送完整状态
+func (r *Room) Replay(playerID string, afterSeq uint64) ([]Message, bool) {
+	if afterSeq > r.seq {
+		return nil, false
+	}
+	if afterSeq < r.seq && (len(r.history) == 0 || r.history[0].seq > afterSeq+1) {
+		return nil, false
+	}
+
+	messages := make([]Message, 0)
+	for _, d := range r.history {
+		if d.seq <= afterSeq {
+			continue
+		}
+		if message, ok := d.messages[playerID]; ok {
+			messages = append(messages, message)
+		}
+	}
+	// 碰杠胡的提示不在广播记录中，玩家还没有做出选择时补上
+	if prompt, ok := r.claimPrompt(playerID); ok {
+		prompt.Seq = r.seq
+		messages = append(messages, prompt)
+	}
+	return messages, true
+}
+
+// publish 为消息分配新的序号，投递给房间内的玩家和观战者并记录下来
+func (r *Room) publish(messageFor func(p *Player) (Message, bool)) {
+	r.seq++
+	d := delivery{seq: r.seq, messages: make(map[string]Message)}
+
+	for _, p := range r.Recipients() {
+		message, ok := messageFor(p)
+		if !ok {
+			continue
+		}
+		message.Seq = r.seq
+		d.messages[p.ID] = message
+		r.emit(p, message)
+	}
+
+	r.history = append(r.history, d)
+	if limit := viper.GetInt("game.messageHistorySize"); len(r.history) > limit {
+		r.history = r.history[len(r.history)-limit:]
+	}
+}
@@ -0,0 +1,337 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"goMahjong/rules"
+	"time"
+)
+
+// 事件类型
+const (
+	EventDeal       = "deal"       // 发牌，开始新的一局
+	EventDingque    = "dingque"    // 定缺
+	EventDraw       = "draw"       // 摸牌，包括杠后补牌
+	EventDiscard    = "discard"    // 出牌
+	EventClaim      = "claim"      // 碰别人打出的牌
+	EventGang       = "gang"       // 杠，Kind区分明杠、暗杠、补杠
+	EventWin        = "win"        // 胡牌
+	EventSettlement = "settlement" // 结算，本局结束
+)
+
+// Event 对局中发生的一件事
+// 房间的对局状态只通过应用事件改变，按顺序折叠同一个房间的事件就能得到相同的状态
+type Event struct {
+	Type       string            `json:"type"`
+	Time       time.Time         `json:"time"`
+	PlayerID   string            `json:"playerID,omitempty"`
+	Tile       string            `json:"tile,omitempty"`
+	From       string            `json:"from,omitempty"` // 被碰、杠、胡的牌是谁打出的
+	Kind       string            `json:"kind,omitempty"` // 杠的种类
+	Suit       string            `json:"suit,omitempty"` // 定缺的花色
+	Deal       *DealDetail       `json:"deal,omitempty"`
+	Win        *WinDetail        `json:"win,omitempty"`
+	Settlement *SettlementDetail `json:"settlement,omitempty"`
+}
+
+// DealDetail 发牌事件的内容
+type DealDetail struct {
+	Seed   int64               `json:"seed"`   // 洗牌和选庄用的随机种子
+	Seats  []SeatRecord        `json:"seats"`  // 按座位顺序
+	Dealer string              `json:"dealer"` // 庄家
+	Hands  map[string][]string `json:"hands"`  // 玩家ID -> 起手牌
+	Wall   []string            `json:"wall"`   // 发牌后剩下的牌墙，按摸牌顺序
+}
+
+// SeatRecord 开局时座位上的玩家
+type SeatRecord struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Score int    `json:"score"` // 开局前的分数
+}
+
+// WinDetail 胡牌事件的内容
+type WinDetail struct {
+	SelfDrawn bool     `json:"selfDrawn"`
+	Fan       int      `json:"fan"`
+	Patterns  []string `json:"patterns"`
+}
+
+// SettlementDetail 结算事件的内容
+type SettlementDetail struct {
+	Deltas map[string]int `json:"deltas"` // 玩家ID -> 本局输赢
+}
+
+// HandEvents 返回当前这一局的事件，从最近一次发牌开始
+func (r *Room) HandEvents() []Event {
+	for i := len(r.Events) - 1; i >= 0; i-- {
+		if r.Events[i].Type == EventDeal {
+			return r.Events[i:]
+		}
+	}
+	return nil
+}
+
+// RebuildRoom 按顺序应用事件，重建房间的对局状态
+// 重建出的房间只包含发牌时在座的玩家，没有连接，也不会发送任何消息
+func RebuildRoom(id string, events []Event) (*Room, error) {
+	return rebuildRoom(id, events, nil)
+}
+
+// rebuildRoom 按顺序应用事件，check 不为空时在应用每个事件之前用它检查事件是否符合规则，
+// 应用之后再检查对局状态是否自洽
+func rebuildRoom(id string, events []Event, check func(r *Room, e Event) error) (*Room, error) {
+	room := &Room{
+		ID:             id,
+		Players:        make([]*Player, 0),
+		GameState:      GameStateWaiting,
+		DiscardedTiles: make([]string, 0),
+	}
+	for i, e := range events {
+		var err error
+		if check != nil {
+			err = check(room, e)
+		}
+		if err == nil {
+			err = room.apply(e)
+		}
+		if err == nil && check != nil {
+			err = room.CheckInvariants()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个事件(%s): %w", i, e.Type, err)
+		}
+		room.Events = append(room.Events, e)
+	}
+	return room, nil
+}
+
+// record 把事件应用到房间状态并追加到事件日志
+// 调用方应该在记录前完成规则校验，应用失败说明出现了不一致的状态
+func (r *Room) record(e Event) error {
+	e.Time = time.Now()
+	if err := r.apply(e); err != nil {
+		return fmt.Errorf("房间 %s 无法应用事件 %s: %w", r.ID, e.Type, err)
+	}
+	r.Events = append(r.Events, e)
+	r.scheduleCoachView()
+	return nil
+}
+
+// apply 应用一个事件，这是唯一修改对局状态的地方
+func (r *Room) apply(e Event) error {
+	if e.Type == EventDeal {
+		return r.applyDeal(e.Deal)
+	}
+	if r.GameState != GameStatePlaying {
+		return errors.New("对局没有在进行")
+	}
+
+	player := r.GetPlayer(e.PlayerID)
+	if player == nil && e.Type != EventSettlement {
+		return errors.New("玩家不存在: " + e.PlayerID)
+	}
+
+	switch e.Type {
+	case EventDingque:
+		player.Missing = e.Suit
+		if r.allDeclared() {
+			// 定缺完成后轮到庄家出牌，庄家起手就胡也算自摸
+			dealer := r.Players[r.DealerIndex]
+			r.lastDrawn = dealer.Tiles[len(dealer.Tiles)-1]
+			r.Phase = PhaseDiscard
+		}
+
+	case EventDraw:
+		if len(r.Tiles) == 0 || r.Tiles[0] != e.Tile {
+			return errors.New("摸的牌与牌墙不一致: " + e.Tile)
+		}
+		r.Tiles = r.Tiles[1:]
+		player.Tiles = append(player.Tiles, e.Tile)
+		r.CurrentPlayerIndex = r.seatIndex(player.ID)
+		r.lastDrawn = e.Tile
+		r.claim = nil
+		r.Phase = PhaseDiscard
+
+	case EventDiscard:
+		tiles, ok := rules.Remove(player.Tiles, e.Tile, 1)
+		if !ok {
+			return errors.New("手牌中没有 " + e.Tile)
+		}
+		player.Tiles = tiles
+		player.Discards = append(player.Discards, e.Tile)
+		r.DiscardedTiles = append(r.DiscardedTiles, e.Tile)
+		r.LastPlayedTile = e.Tile
+		r.lastDrawn = ""
+		// 杠后补牌再打出的牌被胡算杠上炮
+		r.discardAfterGang = r.afterGang
+		r.afterGang = false
+		r.openClaimWindow(player, e.Tile)
+
+	case EventClaim:
+		tiles, ok := rules.Remove(player.Tiles, e.Tile, 2)
+		if !ok {
+			return errors.New("手牌中没有两张 " + e.Tile)
+		}
+		if err := r.takeDiscard(e.From, e.Tile); err != nil {
+			return err
+		}
+		player.Tiles = tiles
+		player.Melds = append(player.Melds, rules.Meld{Kind: rules.MeldPeng, Tile: e.Tile, From: e.From})
+		r.CurrentPlayerIndex = r.seatIndex(player.ID)
+		r.claim = nil
+		r.Phase = PhaseDiscard
+
+	case EventGang:
+		if err := r.applyGang(player, e); err != nil {
+			return err
+		}
+		r.CurrentPlayerIndex = r.seatIndex(player.ID)
+		r.lastDrawn = ""
+		r.afterGang = true
+		r.claim = nil
+		r.Phase = PhaseDraw
+
+	case EventWin:
+		if e.From != "" {
+			if err := r.takeDiscard(e.From, e.Tile); err != nil {
+				return err
+			}
+			player.Tiles = append(player.Tiles, e.Tile)
+		}
+		r.claim = nil
+		r.Phase = PhaseNone
+
+	case EventSettlement:
+		for id, delta := range e.Settlement.Deltas {
+			if p := r.GetPlayer(id); p != nil {
+				p.Score += delta
+			}
+		}
+		r.claim = nil
+		r.Phase = PhaseNone
+		r.GameState = GameStateFinished
+
+	default:
+		return errors.New("未知的事件类型")
+	}
+	return nil
+}
+
+// applyDeal 开始新的一局，座位顺序以发牌事件为准
+func (r *Room) applyDeal(d *DealDetail) error {
+	if d == nil || len(d.Seats) == 0 {
+		return errors.New("发牌事件缺少座位")
+	}
+
+	players := make([]*Player, 0, len(d.Seats))
+	for _, seat := range d.Seats {
+		p := r.GetPlayer(seat.ID)
+		if p == nil {
+			p = NewPlayer(seat.Name)
+			p.ID = seat.ID
+		}
+		p.Score = seat.Score
+		p.Tiles = append([]string(nil), d.Hands[seat.ID]...)
+		p.Discards = make([]string, 0)
+		p.Melds = make([]rules.Meld, 0)
+		p.Missing = ""
+		players = append(players, p)
+	}
+	r.Players = players
+
+	dealer := r.seatIndex(d.Dealer)
+	if dealer < 0 {
+		return errors.New("庄家不在座位上: " + d.Dealer)
+	}
+	r.DealerIndex = dealer
+	r.CurrentPlayerIndex = dealer
+	r.Tiles = append([]string(nil), d.Wall...)
+	r.DiscardedTiles = make([]string, 0)
+	r.LastPlayedTile = ""
+	r.GameState = GameStatePlaying
+	r.Phase = PhaseDingque
+	r.claim = nil
+	r.lastDrawn = ""
+	r.afterGang = false
+	r.discardAfterGang = false
+	return nil
+}
+
+// applyGang 按杠的种类移动牌
+func (r *Room) applyGang(player *Player, e Event) error {
+	switch e.Kind {
+	case rules.MeldMingGang:
+		tiles, ok := rules.Remove(player.Tiles, e.Tile, 3)
+		if !ok {
+			return errors.New("手牌中没有三张 " + e.Tile)
+		}
+		if err := r.takeDiscard(e.From, e.Tile); err != nil {
+			return err
+		}
+		player.Tiles = tiles
+		player.Melds = append(player.Melds, rules.Meld{Kind: e.Kind, Tile: e.Tile, From: e.From})
+	case rules.MeldAnGang:
+		tiles, ok := rules.Remove(player.Tiles, e.Tile, 4)
+		if !ok {
+			return errors.New("手牌中没有四张 " + e.Tile)
+		}
+		player.Tiles = tiles
+		player.Melds = append(player.Melds, rules.Meld{Kind: e.Kind, Tile: e.Tile})
+	case rules.MeldBuGang:
+		meld := player.findPeng(e.Tile)
+		if meld == nil {
+			return errors.New("没有碰过 " + e.Tile)
+		}
+		tiles, ok := rules.Remove(player.Tiles, e.Tile, 1)
+		if !ok {
+			return errors.New("手牌中没有 " + e.Tile)
+		}
+		player.Tiles = tiles
+		meld.Kind = rules.MeldBuGang
+	default:
+		return errors.New("未知的杠: " + e.Kind)
+	}
+	return nil
+}
+
+// takeDiscard 把刚打出的牌从弃牌堆拿走（被碰、杠、胡）
+func (r *Room) takeDiscard(fromID, tile string) error {
+	from := r.GetPlayer(fromID)
+	if from == nil || len(from.Discards) == 0 || from.Discards[len(from.Discards)-1] != tile {
+		return errors.New("最后打出的牌不是 " + tile)
+	}
+	from.Discards = from.Discards[:len(from.Discards)-1]
+	r.DiscardedTiles = r.DiscardedTiles[:len(r.DiscardedTiles)-1]
+	return nil
+}
+
+// allDeclared 是否所有人都定缺了
+func (r *Room) allDeclared() bool {
+	for _, p := range r.Players {
+		if p.Missing == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// seatIndex 返回玩家的座位序号，不在座位上返回-1
+func (r *Room) seatIndex(playerID string) int {
+	for i, p := range r.Players {
+		if p.ID == playerID {
+			return i
+		}
+	}
+	return -1
+}
+
+// findPeng 找到碰过某张牌的副露
+func (p *Player) findPeng(tile string) *rules.Meld {
+	for i := range p.Melds {
+		if p.Melds[i].Kind == rules.MeldPeng && p.Melds[i].Tile == tile {
+			return &p.Melds[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package model
+
+import (
+	"goMahjong/rules"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRoom 创建一个有n个玩家、没有连接的房间
+func newTestRoom(t *testing.T, n int) *Room {
+	room, err := NewRoom("")
+	require.NoError(t, err)
+	for i := 0; i < n; i++ {
+		room.AddPlayer(NewPlayer(string(rune('A' + i))))
+	}
+	room.SetOwner(room.Players[0])
+	return room
+}
+
+// step 随机执行一个合法的操作
+func step(t *testing.T, r *Room, rng *rand.Rand) {
+	switch r.Phase {
+	case PhaseDingque:
+		for _, p := range r.Players {
+			if p.Missing == "" {
+				require.NoError(t, r.HandleDingque(p.ID, fewestSuit(p.Tiles)))
+				return
+			}
+		}
+
+	case PhaseDiscard:
+		p := r.Players[r.CurrentPlayerIndex]
+		options := r.turnOptions(p)
+		if contains(options, ActionHu) && rng.Intn(2) == 0 {
+			require.NoError(t, r.HandlePlayerAction(p.ID, ActionHu, nil))
+			return
+		}
+		if contains(options, ActionGang) && rng.Intn(2) == 0 {
+			require.NoError(t, r.HandlePlayerAction(p.ID, ActionGang, r.gangTiles(p)[:1]))
+			return
+		}
+		choices := make([]string, 0)
+		for _, tile := range p.Tiles {
+			if rules.Suit(tile) == p.Missing {
+				choices = append(choices, tile)
+			}
+		}
+		if len(choices) == 0 {
+			choices = p.Tiles
+		}
+		require.NoError(t, r.HandlePlayTile(p.ID, choices[rng.Intn(len(choices))]))
+
+	case PhaseClaim:
+		for _, p := range r.Players {
+			if options := r.pendingClaimOptions(p.ID); len(options) > 0 {
+				options = append(options, ActionPass)
+				require.NoError(t, r.HandlePlayerAction(p.ID, options[rng.Intn(len(options))], nil))
+				return
+			}
+		}
+
+	default:
+		t.Fatalf("意外的阶段 %q", r.Phase)
+	}
+}
+
+// foldedState 可以由事件重建的全部状态
+type foldedState struct {
+	Players          []Player
+	Tiles            []string
+	DiscardedTiles   []string
+	CurrentPlayer    int
+	Dealer           int
+	Phase            Phase
+	GameState        GameState
+	LastPlayedTile   string
+	LastDrawn        string
+	AfterGang        bool
+	DiscardAfterGang bool
+	ClaimTile        string
+	ClaimOptions     map[string][]string
+}
+
+func foldedStateOf(r *Room) foldedState {
+	s := foldedState{
+		Tiles:            r.Tiles,
+		DiscardedTiles:   r.DiscardedTiles,
+		CurrentPlayer:    r.CurrentPlayerIndex,
+		Dealer:           r.DealerIndex,
+		Phase:            r.Phase,
+		GameState:        r.GameState,
+		LastPlayedTile:   r.LastPlayedTile,
+		LastDrawn:        r.lastDrawn,
+		AfterGang:        r.afterGang,
+		DiscardAfterGang: r.discardAfterGang,
+	}
+	for _, p := range r.Players {
+		s.Players = append(s.Players, Player{
+			ID: p.ID, Name: p.Name, Tiles: p.Tiles, Discards: p.Discards,
+			Melds: p.Melds, Missing: p.Missing, Score: p.Score,
+		})
+	}
+	if r.claim != nil {
+		s.ClaimTile = r.claim.tile
+		s.ClaimOptions = r.claim.options
+	}
+	return s
+}
+
+func TestRebuildRoomFromEvents(t *testing.T) {
+	for seed := int64(1); seed <= 30; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		room := newTestRoom(t, 2+int(seed%3))
+
+		// 同一个房间连续打两局，事件日志跨越多局
+		for hand := 0; hand < 2; hand++ {
+			require.NoError(t, room.StartGameBy(room.Owner.ID))
+			for steps := 0; room.GameState == GameStatePlaying; steps++ {
+				require.Less(t, steps, 1000, "对局没有结束")
+				step(t, room, rng)
+				require.NoError(t, room.CheckInvariants(), "种子 %d 第 %d 步", seed, steps)
+
+				rebuilt, err := RebuildRoom(room.ID, room.Events)
+				require.NoError(t, err)
+				require.Equal(t, foldedStateOf(room), foldedStateOf(rebuilt), "种子 %d 第 %d 步", seed, steps)
+			}
+
+			events := room.HandEvents()
+			require.Equal(t, EventDeal, events[0].Type)
+			last := events[len(events)-1]
+			require.Equal(t, EventSettlement, last.Type)
+			total := 0
+			for _, delta := range last.Settlement.Deltas {
+				total += delta
+			}
+			assert.Zero(t, total, "结算输赢之和应为0")
+		}
+	}
+}
+
+func TestEventsAreTimestampedAndAppendOnly(t *testing.T) {
+	room := newTestRoom(t, 4)
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+	for _, p := range room.Players {
+		require.NoError(t, room.HandleDingque(p.ID, fewestSuit(p.Tiles)))
+	}
+
+	before := append([]Event(nil), room.Events...)
+	// 非法操作不会产生事件
+	assert.Error(t, room.HandleDingque(room.Players[0].ID, rules.SuitWan))
+	assert.Equal(t, before, room.Events)
+
+	require.Len(t, room.Events, 5)
+	assert.Equal(t, EventDeal, room.Events[0].Type)
+	for i, e := range room.Events {
+		assert.False(t, e.Time.IsZero())
+		if i > 0 {
+			assert.Equal(t, EventDingque, e.Type)
+			assert.False(t, e.Time.Before(room.Events[i-1].Time))
+		}
+	}
+}
+
+func TestRebuildRejectsInconsistentEvents(t *testing.T) {
+	room := newTestRoom(t, 2)
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+	events := append([]Event(nil), room.Events...)
+
+	// 摸到的牌与牌墙不一致
+	events = append(events, Event{Type: EventDraw, PlayerID: room.Players[0].ID, Tile: "0x"})
+	_, err := RebuildRoom(room.ID, events)
+	assert.Error(t, err)
+}
+
+func TestCheckInvariantsCatchesBrokenState(t *testing.T) {
+	room := newTestRoom(t, 3)
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+	require.NoError(t, room.CheckInvariants())
+
+	// 丢了一张牌
+	p := room.Players[0]
+	lost := p.Tiles[0]
+	p.Tiles = p.Tiles[1:]
+	assert.Error(t, room.CheckInvariants())
+
+	// 牌数对但手牌张数不对
+	room.Tiles = append(room.Tiles, lost)
+	assert.Error(t, room.CheckInvariants())
+	room.Tiles = room.Tiles[:len(room.Tiles)-1]
+	p.Tiles = append(p.Tiles, lost)
+	require.NoError(t, room.CheckInvariants())
+
+	// 出牌阶段没有定缺
+	for _, p := range room.Players {
+		require.NoError(t, room.HandleDingque(p.ID, fewestSuit(p.Tiles)))
+	}
+	room.Players[1].Missing = ""
+	assert.Error(t, room.CheckInvariants())
+}
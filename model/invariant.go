@@ -0,0 +1,71 @@
+package model
+
+import (
+	"fmt"
+	"goMahjong/rules"
+)
+
+// CheckInvariants 检查对局状态是否自洽，用于自对弈模拟和测试，调用方需持有房间锁
+// 牌的总数守恒：牌墙、手牌、弃牌和副露加起来每种牌正好四张；
+// 手牌张数：当前出牌的玩家手牌加副露（杠算三张）是14张，其他人是13张；
+// 阶段和状态一致：响应阶段有等待响应的出牌，定缺完成后不能碰杠缺门的牌
+func (r *Room) CheckInvariants() error {
+	if r.GameState != GameStatePlaying {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	add := func(tiles []string) {
+		for _, t := range tiles {
+			counts[t]++
+		}
+	}
+	add(r.Tiles)
+	discards := 0
+	for _, p := range r.Players {
+		add(p.Tiles)
+		add(p.Discards)
+		discards += len(p.Discards)
+		for _, m := range p.Melds {
+			for i := 0; i < m.Size(); i++ {
+				counts[m.Tile]++
+			}
+		}
+	}
+	for i := 0; i < rules.TileKinds; i++ {
+		tile := rules.TileAt(i)
+		if counts[tile] != 4 {
+			return fmt.Errorf("%s 有 %d 张", tile, counts[tile])
+		}
+		delete(counts, tile)
+	}
+	for tile := range counts {
+		return fmt.Errorf("出现了不存在的牌 %s", tile)
+	}
+	if discards != len(r.DiscardedTiles) {
+		return fmt.Errorf("弃牌堆有 %d 张，玩家打出的牌有 %d 张", len(r.DiscardedTiles), discards)
+	}
+
+	for i, p := range r.Players {
+		want := 13
+		if (r.Phase == PhaseDiscard || r.Phase == PhaseDingque) && i == r.CurrentPlayerIndex {
+			want = 14
+		}
+		if r.Phase != PhaseDraw && r.Phase != PhaseNone && len(p.Tiles)+3*len(p.Melds) != want {
+			return fmt.Errorf("%s 的手牌和副露有 %d 张，应该是 %d 张", p.Name, len(p.Tiles)+3*len(p.Melds), want)
+		}
+		for _, m := range p.Melds {
+			if p.Missing != "" && rules.Suit(m.Tile) == p.Missing {
+				return fmt.Errorf("%s 碰杠了缺门的牌 %s", p.Name, m.Tile)
+			}
+		}
+		if r.Phase != PhaseDingque && p.Missing == "" {
+			return fmt.Errorf("%s 还没有定缺", p.Name)
+		}
+	}
+
+	if (r.Phase == PhaseClaim) != (r.claim != nil) {
+		return fmt.Errorf("%s 阶段的响应窗口状态不一致", r.Phase)
+	}
+	return nil
+}
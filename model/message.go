@@ -1,7 +1,264 @@
 package model
 
-// Message 表示WebSocket消息
+import (
+	"encoding/json"
+	"goMahjong/rules"
+)
+
+// 服务端发出的消息类型
+const (
+	MsgWelcome          = "welcome"           // 连接建立后告知协商好的协议版本
+	MsgError            = "error"             // 请求处理失败，只发给请求者
+	MsgRoomInfo         = "room_info"         // 房间信息
+	MsgPlayerJoined     = "player_joined"     // 有玩家加入
+	MsgPlayerLeft       = "player_left"       // 有玩家离开
+	MsgNewOwner         = "new_owner"         // 房主变更
+	MsgConnectionStatus = "connection_status" // 玩家在线状态变化
+	MsgChat             = "chat"              // 聊天消息
+	MsgGameStarted      = "game_started"      // 游戏开始
+	MsgGameSnapshot     = "game_snapshot"     // 重连后的完整对局状态
+	MsgTilePlayed       = "tile_played"       // 有玩家出牌
+	MsgNewTile          = "new_tile"          // 自己摸到的牌
+	MsgTurnChanged      = "turn_changed"      // 轮到某个玩家
+	MsgGameOver         = "game_over"         // 本局结束
+	MsgDingque          = "dingque"           // 所有人定缺完成，公布各家缺的花色
+	MsgClaimWindow      = "claim_window"      // 有人出牌后提示能碰、杠、胡的玩家
+	MsgMeld             = "meld"              // 有玩家碰或杠
+	MsgServerShutdown   = "server_shutdown"   // 服务器即将停止
+	MsgRoomClosed       = "room_closed"       // 房间已关闭
+	MsgAutoPlay         = "auto_play"         // 玩家开始或取消托管
+	MsgSpectators       = "spectators"        // 观战人数变化
+	MsgCoachView        = "coach_view"        // 教练视角：延迟公开的所有人手牌
+)
+
+// 客户端发来的消息类型
+const (
+	ReqChat      = "chat"       // 聊天
+	ReqStartGame = "start_game" // 房主开始游戏
+	ReqPlayTile  = "play_tile"  // 出牌
+	ReqAction    = "action"     // 吃碰杠胡
+	ReqLeaveRoom = "leave_room" // 离开房间
+	ReqResync    = "resync"     // 请求补发错过的消息
+	ReqDingque   = "dingque"    // 定缺
+	ReqAddBot    = "add_bot"    // 房主添加机器人
+	ReqAutoPlay  = "auto_play"  // 开始或取消托管
+)
+
+// Message 表示服务端发出的WebSocket消息
 type Message struct {
-	Type string      `json:"type"`
-	Data interface{} `json:"data"`
+	Type    string      `json:"type" pb:"1"`
+	Version int         `json:"v,omitempty" pb:"2"`   // 协议版本，由连接在发送时填写
+	Seq     uint64      `json:"seq,omitempty" pb:"3"` // 房间消息序号，单调递增
+	Data    interface{} `json:"data" pb:"4"`
+}
+
+// Envelope 表示客户端发来的WebSocket消息，Data根据Type再解析成具体的请求结构
+// Data是未解析的原始负载，JSON编码下是JSON文本，protobuf编码下是对应消息的二进制
+type Envelope struct {
+	Type    string          `json:"type" pb:"1"`
+	Version int             `json:"v,omitempty" pb:"2"`
+	Seq     uint64          `json:"seq,omitempty" pb:"3"` // 客户端收到的最后一个序号，用于拒绝过期的操作
+	Data    json.RawMessage `json:"data" pb:"4"`
+}
+
+// WelcomeData 连接建立消息
+type WelcomeData struct {
+	ProtocolVersion int    `json:"protocolVersion" pb:"1"`
+	PlayerID        string `json:"playerID" pb:"2"`
+}
+
+// ErrorData 错误消息
+type ErrorData struct {
+	Code        string `json:"code" pb:"1"`
+	Message     string `json:"message" pb:"2"`
+	RequestType string `json:"requestType,omitempty" pb:"3"` // 出错的请求类型
+}
+
+// PlayerInfo 玩家公开信息
+type PlayerInfo struct {
+	ID        string `json:"id" pb:"1"`
+	Name      string `json:"name" pb:"2"`
+	Score     int    `json:"score" pb:"3"`
+	Connected bool   `json:"connected" pb:"4"`
+	IsOwner   bool   `json:"isOwner" pb:"5"`
+	IsBot     bool   `json:"isBot,omitempty" pb:"6"`
+	BotLevel  string `json:"botLevel,omitempty" pb:"7"` // 机器人的难度：easy、normal、hard、expert
+	AutoPlay  bool   `json:"autoPlay,omitempty" pb:"8"` // 是否托管
+}
+
+// RoomInfoData 房间信息
+type RoomInfoData struct {
+	ID         string       `json:"id" pb:"1"`
+	Players    []PlayerInfo `json:"players" pb:"2"`
+	Owner      *PlayerInfo  `json:"owner" pb:"3"`
+	GameState  GameState    `json:"gameState" pb:"4"`
+	Spectators int          `json:"spectators" pb:"5"` // 观战人数
+}
+
+// PlayerJoinedData 玩家加入消息
+type PlayerJoinedData struct {
+	Player PlayerInfo `json:"player" pb:"1"`
+}
+
+// PlayerLeftData 玩家离开消息
+type PlayerLeftData struct {
+	PlayerID string `json:"playerID" pb:"1"`
+}
+
+// NewOwnerData 房主变更消息
+type NewOwnerData struct {
+	OwnerID string `json:"ownerID" pb:"1"`
+}
+
+// ConnectionStatusData 玩家在线状态消息
+type ConnectionStatusData struct {
+	PlayerID  string `json:"playerID" pb:"1"`
+	Connected bool   `json:"connected" pb:"2"`
+	Reason    string `json:"reason,omitempty" pb:"3"` // 掉线原因：timeout、closed 或 left（对局中主动离开）
+}
+
+// AutoPlayData 托管状态变化消息
+type AutoPlayData struct {
+	PlayerID string `json:"playerID" pb:"1"`
+	Enabled  bool   `json:"enabled" pb:"2"`
+	Reason   string `json:"reason,omitempty" pb:"3"` // 开始托管的原因：disconnected、left 或 manual
+}
+
+// ChatData 聊天消息
+type ChatData struct {
+	PlayerID   string `json:"playerID" pb:"1"`
+	PlayerName string `json:"playerName" pb:"2"`
+	Content    string `json:"content" pb:"3"`
+	Spectator  bool   `json:"spectator,omitempty" pb:"4"` // 是否是观战者发的
+}
+
+// SpectatorsData 观战人数变化
+type SpectatorsData struct {
+	Count int `json:"count" pb:"1"`
+}
+
+// CoachViewData 教练视角，内容是 Delay 秒之前的对局状态
+type CoachViewData struct {
+	Hands           []RevealedHand    `json:"hands" pb:"1"`   // 所有人的手牌
+	Missing         map[string]string `json:"missing" pb:"2"` // 玩家ID -> 定缺的花色，还没定缺的不在其中
+	Phase           Phase             `json:"phase" pb:"3"`
+	CurrentPlayerID string            `json:"currentPlayerID" pb:"4"`
+	Delay           int               `json:"delay" pb:"5"`
+}
+
+// SeatInfo 对局中某个座位的公开信息
+type SeatInfo struct {
+	ID        string       `json:"id" pb:"1"`
+	Name      string       `json:"name" pb:"2"`
+	Score     int          `json:"score" pb:"3"`
+	Connected bool         `json:"connected" pb:"4"`
+	IsOwner   bool         `json:"isOwner" pb:"5"`
+	TileCount int          `json:"tileCount" pb:"6"`
+	Discards  []string     `json:"discards" pb:"7"`
+	Melds     []rules.Meld `json:"melds" pb:"8"`
+	Missing   string       `json:"missing,omitempty" pb:"9"` // 定缺的花色，所有人定缺完成后才公开
+	IsBot     bool         `json:"isBot,omitempty" pb:"10"`
+	BotLevel  string       `json:"botLevel,omitempty" pb:"11"`
+	AutoPlay  bool         `json:"autoPlay,omitempty" pb:"12"`
+	TimeBank  int          `json:"timeBank,omitempty" pb:"13"` // 本局剩余的时间银行（秒）
+}
+
+// GameStateData 对局公开状态
+// 嵌入它的消息自己的字段号从16开始，给它留出扩展的空间
+type GameStateData struct {
+	GameState          GameState  `json:"gameState" pb:"1"`
+	Players            []SeatInfo `json:"players" pb:"2"`
+	CurrentPlayerIndex int        `json:"currentPlayerIndex" pb:"3"`
+	CurrentPlayerID    string     `json:"currentPlayerID" pb:"4"`
+	DiscardedTiles     []string   `json:"discardedTiles" pb:"5"`
+	RemainingTiles     int        `json:"remainingTiles" pb:"6"`
+	Phase              Phase      `json:"phase" pb:"7"`
+	DealerID           string     `json:"dealerID" pb:"8"`
+	Seconds            int        `json:"seconds,omitempty" pb:"9"` // 当前阶段剩余的秒数，包括时间银行，不计时为0
+}
+
+// GameSnapshotData 某个玩家视角的完整对局状态
+type GameSnapshotData struct {
+	GameStateData
+	LastPlayedTile string   `json:"lastPlayedTile" pb:"16"`
+	Tiles          []string `json:"tiles" pb:"17"`             // 自己的手牌
+	Options        []string `json:"options,omitempty" pb:"18"` // 自己当前可以执行的动作
+}
+
+// GameStartedData 开局消息，只包含收件人自己的手牌
+type GameStartedData struct {
+	GameStateData
+	Tiles []string `json:"tiles" pb:"16"`
+}
+
+// TilePlayedData 出牌消息
+type TilePlayedData struct {
+	PlayerID string `json:"playerID" pb:"1"`
+	Tile     string `json:"tile" pb:"2"`
+}
+
+// NewTileData 摸牌消息，牌面只发给摸牌的玩家
+type NewTileData struct {
+	PlayerID string `json:"playerID" pb:"1"`
+	Tile     string `json:"tile,omitempty" pb:"2"`
+}
+
+// TurnChangedData 轮转消息
+type TurnChangedData struct {
+	PlayerID string   `json:"playerID" pb:"1"`
+	Options  []string `json:"options,omitempty" pb:"2"`  // 只发给当前玩家：可以自摸或杠
+	Seconds  int      `json:"seconds,omitempty" pb:"3"`  // 出牌的基础时间（秒），超时先用时间银行，不计时为0
+	TimeBank int      `json:"timeBank,omitempty" pb:"4"` // 当前玩家剩余的时间银行（秒）
+}
+
+// GameOverData 本局结果
+type GameOverData struct {
+	Winner      *PlayerInfo    `json:"winner" pb:"1"`                // 流局时为null
+	Scores      map[string]int `json:"scores" pb:"2"`                // 玩家ID -> 分数
+	Deltas      map[string]int `json:"deltas" pb:"3"`                // 玩家ID -> 本局输赢
+	WinningTile string         `json:"winningTile,omitempty" pb:"4"` // 胡的那张牌
+	DiscarderID string         `json:"discarderID,omitempty" pb:"5"` // 点炮的玩家，自摸为空
+	SelfDrawn   bool           `json:"selfDrawn" pb:"6"`
+	Fan         int            `json:"fan" pb:"7"`
+	Patterns    []string       `json:"patterns" pb:"8"`            // 番型
+	Hands       []RevealedHand `json:"hands" pb:"9"`               // 本局结束后公开所有人的手牌
+	ReplayID    string         `json:"replayID,omitempty" pb:"10"` // 回放ID，没有保存回放时为空
+}
+
+// RevealedHand 结束时公开的手牌
+type RevealedHand struct {
+	PlayerID string   `json:"playerID" pb:"1"`
+	Tiles    []string `json:"tiles" pb:"2"`
+}
+
+// DingqueData 定缺结果
+type DingqueData struct {
+	Suits map[string]string `json:"suits" pb:"1"` // 玩家ID -> 缺的花色
+}
+
+// ClaimWindowData 碰杠胡的提示，只发给能碰杠胡的玩家，不占用序号
+// 其他人收不到，否则就能知道有人在等这张牌
+type ClaimWindowData struct {
+	Tile    string   `json:"tile" pb:"1"`
+	From    string   `json:"from" pb:"2"` // 出牌的玩家
+	Options []string `json:"options" pb:"3"`
+	Seconds int      `json:"seconds,omitempty" pb:"4"` // 响应的时间（秒），超时自动过，不计时为0
+}
+
+// MeldData 碰杠消息，暗杠的牌面只有本人能看到
+type MeldData struct {
+	PlayerID string `json:"playerID" pb:"1"`
+	Kind     string `json:"kind" pb:"2"`
+	Tile     string `json:"tile,omitempty" pb:"3"`
+	From     string `json:"from,omitempty" pb:"4"`
+}
+
+// RoomClosedData 房间已关闭，随后断开连接；Reason 为 idle（太久没有人在线）或 finished（打完之后太久没有再开局）
+type RoomClosedData struct {
+	Reason string `json:"reason" pb:"1"`
+}
+
+// ServerShutdownData 服务器即将停止，Seconds 秒后断开所有连接
+type ServerShutdownData struct {
+	Seconds int `json:"seconds" pb:"1"`
 }
@@ -1,42 +1,51 @@
 package model
 
 import (
+	"goMahjong/rules"
+	"time"
+
 	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
 )
 
 // Player 表示麻将游戏中的一个玩家
 type Player struct {
-	ID    string          `json:"id"`
-	Name  string          `json:"name"`
-	Conn  *websocket.Conn `json:"-"`
-	Tiles []string        `json:"tiles,omitempty"` // 玩家手牌
-	Score int             `json:"score"`           // 玩家分数
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Tiles     []string     `json:"-"`                  // 玩家手牌，只通过座位视图发给本人
+	Discards  []string     `json:"discards"`           // 玩家自己打出的牌
+	Melds     []rules.Meld `json:"melds"`              // 碰、杠的牌
+	Missing   string       `json:"missing"`            // 定缺的花色
+	Score     int          `json:"score"`              // 玩家分数
+	Connected bool         `json:"connected"`          // 是否在线，断线不等于离开房间
+	Bot       bool         `json:"bot,omitempty"`      // 是否是机器人
+	BotLevel  string       `json:"botLevel,omitempty"` // 机器人的难度
+	AutoPlay  bool         `json:"autoPlay,omitempty"` // 是否托管
+	Coach     bool         `json:"-"`                  // 观战者是否是教练，教练可以看到延迟公开的所有人手牌
+
+	timeBank time.Duration // 本局剩余的时间银行
 }
 
 // NewPlayer 创建一个新玩家
 func NewPlayer(name string) *Player {
 	return &Player{
-		ID:    uuid.New().String(),
-		Name:  name,
-		Tiles: make([]string, 0),
-		Score: 0,
+		ID:       uuid.New().String(),
+		Name:     name,
+		Tiles:    make([]string, 0),
+		Discards: make([]string, 0),
+		Melds:    make([]rules.Meld, 0),
+		Score:    0,
 	}
 }
 
 // GetPublicInfo 获取玩家公开信息
-func (p *Player) GetPublicInfo() map[string]interface{} {
-	return map[string]interface{}{
-		"id":    p.ID,
-		"name":  p.Name,
-		"score": p.Score,
-	}
-}
-
-// SendMessage 向玩家发送消息
-func (p *Player) SendMessage(message Message) error {
-	if p.Conn == nil {
-		return nil
+func (p *Player) GetPublicInfo() PlayerInfo {
+	return PlayerInfo{
+		ID:        p.ID,
+		Name:      p.Name,
+		Score:     p.Score,
+		Connected: p.Connected,
+		IsBot:     p.Bot,
+		BotLevel:  p.BotLevel,
+		AutoPlay:  p.AutoPlay,
 	}
-	return p.Conn.WriteJSON(message)
 }
@@ -0,0 +1,245 @@
+package model
+
+import (
+	"errors"
+	"goMahjong/rules"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playRandomHand 用随机的合法操作打完一局
+func playRandomHand(t *testing.T, room *Room, rng *rand.Rand) {
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+	for steps := 0; room.GameState == GameStatePlaying; steps++ {
+		require.Less(t, steps, 1000, "对局没有结束")
+		step(t, room, rng)
+	}
+}
+
+// 每一个事件之后对局状态都自洽，而不只是每一步操作之后
+func TestInvariantsHoldAfterEveryEvent(t *testing.T) {
+	for seed := int64(1); seed <= 40; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		room := newTestRoom(t, 2+int(seed%3))
+		playRandomHand(t, room, rng)
+
+		for i := 1; i <= len(room.Events); i++ {
+			rebuilt, err := RebuildRoom(room.ID, room.Events[:i])
+			require.NoError(t, err)
+			require.NoError(t, rebuilt.CheckInvariants(), "种子 %d 第 %d 个事件 %s", seed, i, room.Events[i-1].Type)
+		}
+	}
+}
+
+// illegalAction 一个当前状态下不合法的操作和期望的错误码
+type illegalAction struct {
+	name string
+	code string
+	do   func() error
+}
+
+// illegalActions 当前状态下应该被拒绝的操作
+func illegalActions(r *Room, rng *rand.Rand) []illegalAction {
+	actions := make([]illegalAction, 0)
+	current := r.Players[r.CurrentPlayerIndex]
+	for _, p := range r.Players {
+		p := p
+		tile := p.Tiles[rng.Intn(len(p.Tiles))]
+
+		// 只有当前玩家在出牌阶段可以出牌
+		if r.Phase != PhaseDiscard {
+			actions = append(actions, illegalAction{"非出牌阶段出牌", ErrCodeInvalidState, func() error {
+				return r.HandlePlayTile(p.ID, tile)
+			}})
+		} else if p != current {
+			actions = append(actions, illegalAction{"不是自己的回合出牌", ErrCodeNotYourTurn, func() error {
+				return r.HandlePlayTile(p.ID, tile)
+			}})
+		}
+
+		// 没有可以响应的动作时不能碰杠胡
+		if r.Phase == PhaseClaim && len(r.pendingClaimOptions(p.ID)) == 0 {
+			actions = append(actions, illegalAction{"没有选项时响应", ErrCodeInvalidState, func() error {
+				return r.HandlePlayerAction(p.ID, ActionPeng, nil)
+			}})
+		}
+		if r.Phase != PhaseDingque {
+			actions = append(actions, illegalAction{"定缺阶段之后定缺", ErrCodeInvalidState, func() error {
+				return r.HandleDingque(p.ID, rules.SuitWan)
+			}})
+		}
+	}
+
+	if r.Phase == PhaseDiscard {
+		// 手里没有的牌
+		for i := 0; i < rules.TileKinds; i++ {
+			if tile := rules.TileAt(i); rules.Count(current.Tiles, tile) == 0 {
+				actions = append(actions, illegalAction{"打出手里没有的牌", ErrCodeInvalidTile, func() error {
+					return r.HandlePlayTile(current.ID, tile)
+				}})
+				break
+			}
+		}
+		// 有缺门的牌时打别的牌
+		if rules.CountSuit(current.Tiles, current.Missing) > 0 {
+			for _, tile := range current.Tiles {
+				if rules.Suit(tile) != current.Missing {
+					actions = append(actions, illegalAction{"缺门没打完", ErrCodeInvalidTile, func() error {
+						return r.HandlePlayTile(current.ID, tile)
+					}})
+					break
+				}
+			}
+		}
+		// 不能胡的时候胡
+		if !contains(r.turnOptions(current), ActionHu) {
+			actions = append(actions, illegalAction{"不能胡的时候自摸", ErrCodeInvalidState, func() error {
+				return r.HandlePlayerAction(current.ID, ActionHu, nil)
+			}})
+		}
+	}
+	return actions
+}
+
+// 随机的非法操作都被拒绝，不产生事件也不改变状态
+func TestIllegalActionsAreRejectedWithoutEvents(t *testing.T) {
+	for seed := int64(1); seed <= 30; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		room := newTestRoom(t, 2+int(seed%3))
+		require.NoError(t, room.StartGameBy(room.Owner.ID))
+
+		for steps := 0; room.GameState == GameStatePlaying; steps++ {
+			require.Less(t, steps, 1000, "对局没有结束")
+			for _, action := range illegalActions(room, rng) {
+				before := foldedStateOf(room)
+				events := len(room.Events)
+
+				err := action.do()
+				var gameErr *GameError
+				require.True(t, errors.As(err, &gameErr), "种子 %d %s 应该被拒绝", seed, action.name)
+				assert.Equal(t, action.code, gameErr.Code, "种子 %d %s", seed, action.name)
+				require.Len(t, room.Events, events, "种子 %d %s 产生了事件", seed, action.name)
+				require.Equal(t, before, foldedStateOf(room), "种子 %d %s 改变了状态", seed, action.name)
+			}
+			step(t, room, rng)
+		}
+	}
+}
+
+// 对局中的任何时刻只有一个玩家需要行动：定缺阶段是还没定缺的人，出牌阶段是当前玩家，响应阶段是有选项的人
+func TestOnlyTheCurrentPlayerHasTurnOptions(t *testing.T) {
+	for seed := int64(1); seed <= 30; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		room := newTestRoom(t, 4)
+		require.NoError(t, room.StartGameBy(room.Owner.ID))
+
+		for steps := 0; room.GameState == GameStatePlaying; steps++ {
+			require.Less(t, steps, 1000, "对局没有结束")
+			for i, p := range room.Players {
+				if room.Phase != PhaseDiscard || i != room.CurrentPlayerIndex {
+					assert.Empty(t, room.turnOptions(p), "种子 %d 第 %d 步", seed, steps)
+				}
+				if room.Phase != PhaseClaim {
+					assert.Empty(t, room.pendingClaimOptions(p.ID))
+				}
+			}
+			if room.Phase == PhaseClaim {
+				assert.Empty(t, room.pendingClaimOptions(room.claim.from), "出牌的人不能响应自己的牌")
+			}
+			step(t, room, rng)
+		}
+	}
+}
+
+// scriptedDeal 按指定的起手牌发牌，牌墙先摸 wallHead 中的牌，剩下的牌按顺序排在后面
+// 第一个座位是庄家，手牌用空格分隔
+func scriptedDeal(t *testing.T, room *Room, hands []string, wallHead string) *DealDetail {
+	rest := rules.NewWall()
+	take := func(tiles []string) {
+		for _, tile := range tiles {
+			var ok bool
+			rest, ok = rules.Remove(rest, tile, 1)
+			require.True(t, ok, "%s 超过四张", tile)
+		}
+	}
+
+	deal := &DealDetail{Hands: make(map[string][]string)}
+	for i, p := range room.Players {
+		deal.Seats = append(deal.Seats, SeatRecord{ID: p.ID, Name: p.Name, Score: p.Score})
+		deal.Hands[p.ID] = strings.Fields(hands[i])
+		take(deal.Hands[p.ID])
+	}
+	deal.Dealer = room.Players[0].ID
+	head := strings.Fields(wallHead)
+	take(head)
+	deal.Wall = append(head, rest...)
+	return deal
+}
+
+// lastSettlement 本局结算的得分变化
+func lastSettlement(t *testing.T, room *Room) map[string]int {
+	e := room.Events[len(room.Events)-1]
+	require.Equal(t, EventSettlement, e.Type)
+	total := 0
+	for _, delta := range e.Settlement.Deltas {
+		total += delta
+	}
+	require.Zero(t, total, "得分变化之和不为0")
+	return e.Settlement.Deltas
+}
+
+// 庄家天胡清一色对对胡：对对胡1番、清一色2番、自摸1番，封顶4番，两家各付16分
+func TestScriptedDealerWinsOnTheDeal(t *testing.T) {
+	room := newTestRoom(t, 3)
+	a, b, c := room.Players[0], room.Players[1], room.Players[2]
+	require.NoError(t, room.StartGameWithDeal(scriptedDeal(t, room, []string{
+		"1t 1t 1t 3t 3t 3t 5t 5t 5t 7t 7t 7t 9t 9t",
+		"1w 2w 3w 4w 5w 6w 7w 8w 9w 1p 2p 3p 4p",
+		"1w 2w 3w 4w 5w 6w 7w 8w 9w 5p 6p 7p 8p",
+	}, "")))
+	require.NoError(t, room.HandleDingque(a.ID, rules.SuitWan))
+	require.NoError(t, room.HandleDingque(b.ID, rules.SuitTiao))
+	require.NoError(t, room.HandleDingque(c.ID, rules.SuitTiao))
+	require.NoError(t, room.CheckInvariants())
+
+	require.Contains(t, room.turnOptions(a), ActionHu)
+	require.NoError(t, room.HandlePlayerAction(a.ID, ActionHu, nil))
+
+	win := room.Events[len(room.Events)-2]
+	require.Equal(t, EventWin, win.Type)
+	assert.Equal(t, 4, win.Win.Fan)
+	assert.Equal(t, []string{rules.PatternAllTriplets, rules.PatternPureSuit, rules.PatternSelfDrawn}, win.Win.Patterns)
+	assert.Equal(t, map[string]int{a.ID: 32, b.ID: -16, c.ID: -16}, lastSettlement(t, room))
+}
+
+// 明杠之后杠上开花：平胡0番、根1番、自摸1番、杠上开花1番共3番，自摸每家付8分，点杠的人另付2分
+func TestScriptedMingGangThenGangFlower(t *testing.T) {
+	room := newTestRoom(t, 2)
+	a, b := room.Players[0], room.Players[1]
+	require.NoError(t, room.StartGameWithDeal(scriptedDeal(t, room, []string{
+		"5p 1w 1w 2w 2w 3w 3w 4w 4w 6w 6w 7w 8w 9p",
+		"5p 5p 5p 1t 2t 3t 4t 5t 6t 7t 8t 9t 2p",
+	}, "2p")))
+	require.NoError(t, room.HandleDingque(a.ID, rules.SuitTiao))
+	require.NoError(t, room.HandleDingque(b.ID, rules.SuitWan))
+
+	require.NoError(t, room.HandlePlayTile(a.ID, "5p"))
+	require.Equal(t, PhaseClaim, room.Phase)
+	require.Contains(t, room.pendingClaimOptions(b.ID), ActionGang)
+	require.NoError(t, room.HandlePlayerAction(b.ID, ActionGang, nil))
+	require.NoError(t, room.CheckInvariants())
+
+	// 杠后补的牌正好胡
+	require.Equal(t, b, room.Players[room.CurrentPlayerIndex])
+	require.Equal(t, "2p", room.lastDrawn)
+	require.NoError(t, room.HandlePlayerAction(b.ID, ActionHu, nil))
+
+	win := room.Events[len(room.Events)-2]
+	assert.Equal(t, 3, win.Win.Fan)
+	assert.Equal(t, []string{rules.PatternPingHu, rules.PatternRoot, rules.PatternSelfDrawn, rules.PatternGangFlower}, win.Win.Patterns)
+	assert.Equal(t, map[string]int{a.ID: -10, b.ID: 10}, lastSettlement(t, room))
+}
@@ -0,0 +1,190 @@
+package model
+
+import (
+	"fmt"
+	"goMahjong/rules"
+	"reflect"
+)
+
+// 协议版本，客户端在连接时通过 v 参数声明自己支持的最高版本
+const (
+	ProtocolVersion    = 1 // 服务端支持的最高版本
+	MinProtocolVersion = 1 // 服务端支持的最低版本
+)
+
+// NegotiateVersion 根据客户端声明的版本选出双方都支持的版本
+// 客户端未声明时按最低版本处理，返回false表示无法兼容
+func NegotiateVersion(clientVersion int) (int, bool) {
+	if clientVersion == 0 {
+		return MinProtocolVersion, true
+	}
+	if clientVersion < MinProtocolVersion {
+		return 0, false
+	}
+	if clientVersion > ProtocolVersion {
+		return ProtocolVersion, true
+	}
+	return clientVersion, true
+}
+
+// 错误码
+const (
+	ErrCodeBadRequest         = "bad_request"         // 消息格式错误
+	ErrCodeUnknownType        = "unknown_type"        // 未知的消息类型
+	ErrCodeUnsupportedVersion = "unsupported_version" // 协议版本不匹配
+	ErrCodeForbidden          = "forbidden"           // 没有权限
+	ErrCodeInvalidState       = "invalid_state"       // 当前状态下不能执行该操作
+	ErrCodeNotYourTurn        = "not_your_turn"       // 还没轮到该玩家
+	ErrCodeInvalidTile        = "invalid_tile"        // 牌不合法或不在手中
+	ErrCodeStaleAction        = "stale_action"        // 请求基于过期的对局状态
+	ErrCodeInternal           = "internal_error"      // 服务端内部错误
+)
+
+// GameError 可以返回给客户端的错误
+type GameError struct {
+	Code    string
+	Message string
+}
+
+func (e *GameError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// NewGameError 创建一个游戏错误
+func NewGameError(code, message string) *GameError {
+	return &GameError{Code: code, Message: message}
+}
+
+// 玩家可以执行的动作，四川麻将不能吃
+const (
+	ActionPeng = "peng"
+	ActionGang = "gang"
+	ActionHu   = "hu"
+	ActionPass = "pass"
+)
+
+// ChatRequest 聊天请求
+type ChatRequest struct {
+	Content string `json:"content" pb:"1"`
+}
+
+// Validate 校验请求
+func (r *ChatRequest) Validate() error {
+	if r.Content == "" {
+		return NewGameError(ErrCodeBadRequest, "聊天内容不能为空")
+	}
+	return nil
+}
+
+// StartGameRequest 开始游戏请求
+type StartGameRequest struct{}
+
+// PlayTileRequest 出牌请求
+type PlayTileRequest struct {
+	Tile string `json:"tile" pb:"1"`
+}
+
+// Validate 校验请求
+func (r *PlayTileRequest) Validate() error {
+	if !IsValidTile(r.Tile) {
+		return NewGameError(ErrCodeInvalidTile, "无效的牌: "+r.Tile)
+	}
+	return nil
+}
+
+// ActionRequest 碰杠胡请求，杠牌时Tiles给出要杠的牌
+type ActionRequest struct {
+	Action string   `json:"action" pb:"1"`
+	Tiles  []string `json:"tiles" pb:"2"`
+}
+
+// Validate 校验请求
+func (r *ActionRequest) Validate() error {
+	switch r.Action {
+	case ActionPeng, ActionGang, ActionHu, ActionPass:
+	default:
+		return NewGameError(ErrCodeBadRequest, "未知的动作: "+r.Action)
+	}
+	for _, t := range r.Tiles {
+		if !IsValidTile(t) {
+			return NewGameError(ErrCodeInvalidTile, "无效的牌: "+t)
+		}
+	}
+	return nil
+}
+
+// DingqueRequest 定缺请求
+type DingqueRequest struct {
+	Suit string `json:"suit" pb:"1"`
+}
+
+// Validate 校验请求
+func (r *DingqueRequest) Validate() error {
+	for _, suit := range rules.Suits {
+		if r.Suit == suit {
+			return nil
+		}
+	}
+	return NewGameError(ErrCodeBadRequest, "无效的花色: "+r.Suit)
+}
+
+// AddBotRequest 房主添加机器人，只能在开局前添加，Level 为空时是 normal
+type AddBotRequest struct {
+	Level string `json:"level,omitempty" pb:"1"`
+}
+
+// AutoPlayRequest 对局中开始或取消托管
+type AutoPlayRequest struct {
+	Enabled bool `json:"enabled" pb:"1"`
+}
+
+// LeaveRoomRequest 离开房间请求
+type LeaveRoomRequest struct{}
+
+// ResyncRequest 补发请求
+type ResyncRequest struct {
+	AfterSeq uint64 `json:"afterSeq" pb:"1"`
+}
+
+// validator 需要额外校验的请求
+type validator interface {
+	Validate() error
+}
+
+// DecodeRequest 按消息类型把Data解析成具体的请求结构并校验
+// version 是连接协商好的协议版本，消息里带了不同的版本号会被拒绝，codec 是连接使用的编码
+func DecodeRequest(env Envelope, version int, codec Codec) (interface{}, error) {
+	if env.Version != 0 && env.Version != version {
+		return nil, NewGameError(ErrCodeUnsupportedVersion, fmt.Sprintf("连接使用的协议版本为 %d", version))
+	}
+
+	// 请求结构由协议目录决定
+	spec, ok := FindMessageSpec(DirectionClient, env.Type)
+	if !ok {
+		return nil, NewGameError(ErrCodeUnknownType, "未知的消息类型: "+env.Type)
+	}
+
+	req := reflect.New(reflect.TypeOf(spec.Payload)).Interface()
+	if err := codec.DecodePayload(env.Data, req); err != nil {
+		return nil, NewGameError(ErrCodeBadRequest, "消息格式错误: "+err.Error())
+	}
+
+	if v, ok := req.(validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// IsValidTile 判断是否是合法的牌编码，如 "1t"、"9w"
+func IsValidTile(tile string) bool {
+	if len(tile) != 2 || tile[0] < '1' || tile[0] > '9' {
+		return false
+	}
+	switch tile[1] {
+	case 't', 'p', 'w':
+		return true
+	}
+	return false
+}
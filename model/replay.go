@@ -0,0 +1,152 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"goMahjong/rules"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+// ReplayVersion 回放文件格式的版本，格式有不兼容的变化时加一
+const ReplayVersion = 1
+
+// RulesetSichuanSingleWin 四川麻将定缺打法，第一个人胡牌或牌摸完时这一局就结束：
+// 没有血战到底的继续胡牌，流局时也不查叫、不查花猪（本项目目前只有这一种规则）
+const RulesetSichuanSingleWin = "sichuan-single-win"
+
+// Replay 一局的回放文件，包含重放这一局需要的全部信息
+type Replay struct {
+	Version     int            `json:"version"`
+	ID          string         `json:"id"`
+	RoomID      string         `json:"roomID"`
+	Ruleset     Ruleset        `json:"ruleset"`
+	Seed        int64          `json:"seed"`        // 洗牌和选庄的随机种子
+	Seats       []SeatRecord   `json:"seats"`       // 按座位顺序，分数为开局前的分数
+	Events      []Event        `json:"events"`      // 从发牌到结算的全部事件
+	FinalScores map[string]int `json:"finalScores"` // 玩家ID -> 结算后的分数
+	CreatedAt   time.Time      `json:"createdAt"`
+}
+
+// Ruleset 这一局使用的规则和计分参数
+type Ruleset struct {
+	Name      string `json:"name"`
+	BaseScore int    `json:"baseScore"` // 底分
+	MaxFan    int    `json:"maxFan"`    // 封顶番数
+}
+
+// NewReplay 把房间刚结束的这一局打包成回放
+func NewReplay(r *Room) (*Replay, error) {
+	events := r.HandEvents()
+	if len(events) == 0 || events[len(events)-1].Type != EventSettlement {
+		return nil, errors.New("这一局还没有结束")
+	}
+	deal := events[0].Deal
+
+	scores := make(map[string]int)
+	for _, seat := range deal.Seats {
+		scores[seat.ID] = seat.Score + events[len(events)-1].Settlement.Deltas[seat.ID]
+	}
+
+	return &Replay{
+		Version: ReplayVersion,
+		ID:      uuid.New().String(),
+		RoomID:  r.ID,
+		Ruleset: Ruleset{
+			Name:      RulesetSichuanSingleWin,
+			BaseScore: viper.GetInt("game.baseScore"),
+			MaxFan:    viper.GetInt("game.maxFan"),
+		},
+		Seed:        deal.Seed,
+		Seats:       deal.Seats,
+		Events:      append([]Event(nil), events...),
+		FinalScores: scores,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Rebuild 重放回放中的事件，得到这一局结束时的房间状态
+func (rp *Replay) Rebuild() (*Room, error) {
+	return RebuildRoom(rp.RoomID, rp.Events)
+}
+
+// Verify 按规则重放回放，用于检查导入的外部牌谱：Rebuild 只检查牌的去向，
+// 这里还要求起手牌张数正确、定缺有效、胡牌合法，番数和结算与按 Ruleset 算出的一致
+func (rp *Replay) Verify() (*Room, error) {
+	if len(rp.Events) == 0 || rp.Events[0].Type != EventDeal || rp.Events[0].Deal == nil {
+		return nil, errors.New("回放没有从发牌开始")
+	}
+	if n := len(rp.Events[0].Deal.Seats); n < 2 || n > MaxPlayers {
+		return nil, fmt.Errorf("座位数 %d 无效", n)
+	}
+	room, err := rebuildRoom(rp.RoomID, rp.Events, func(r *Room, e Event) error {
+		return r.checkRules(e, rp.Ruleset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if room.GameState != GameStateFinished {
+		return nil, errors.New("这一局没有结算")
+	}
+	return room, nil
+}
+
+// checkRules 检查下一个事件是否是对局引擎在当前状态下会产生的，只检查规则相关的部分，牌的去向由 apply 检查
+func (r *Room) checkRules(e Event, ruleset Ruleset) error {
+	if e.Type != EventDeal && r.GameState != GameStatePlaying {
+		return errors.New("对局没有在进行")
+	}
+	player := r.GetPlayer(e.PlayerID)
+
+	switch e.Type {
+	case EventDeal:
+		if r.GameState == GameStatePlaying {
+			return errors.New("这一局还没有结束")
+		}
+	case EventDingque:
+		if r.Phase != PhaseDingque || player == nil || player.Missing != "" {
+			return errors.New("现在不能定缺")
+		}
+		if !contains(rules.Suits, e.Suit) {
+			return errors.New("无效的花色: " + e.Suit)
+		}
+	case EventWin:
+		if player == nil || e.Win == nil {
+			return errors.New("胡牌事件不完整")
+		}
+		if e.From == "" {
+			if e.Tile != r.lastDrawn || !contains(r.turnOptions(player), ActionHu) {
+				return errors.New(player.Name + " 不能自摸 " + e.Tile)
+			}
+		} else if r.claim == nil || r.claim.from != e.From || r.claim.tile != e.Tile || !contains(r.claim.options[player.ID], ActionHu) {
+			return errors.New(player.Name + " 不能胡 " + e.Tile)
+		}
+		fan, patterns := r.winFan(player, e.Tile, e.From)
+		if e.Win.SelfDrawn != (e.From == "") || e.Win.Fan != fan || !reflect.DeepEqual(e.Win.Patterns, patterns) {
+			return fmt.Errorf("番数应该是 %d 番 %v", fan, patterns)
+		}
+	case EventSettlement:
+		if e.Settlement == nil {
+			return errors.New("结算事件不完整")
+		}
+		var win *Event
+		if last := r.Events[len(r.Events)-1]; last.Type == EventWin {
+			win = &last
+		} else if len(r.Tiles) > 0 {
+			return errors.New("没有人胡牌，牌也还没有摸完")
+		}
+		deltas := r.settlementDeltas(win, ruleset.BaseScore, ruleset.MaxFan)
+		if len(e.Settlement.Deltas) != len(deltas) {
+			return errors.New("结算的人数与座位数不一致")
+		}
+		for _, p := range r.Players {
+			if got, ok := e.Settlement.Deltas[p.ID]; !ok || got != deltas[p.ID] {
+				return fmt.Errorf("%s 的输赢应该是 %d", p.Name, deltas[p.ID])
+			}
+		}
+	}
+	return nil
+}
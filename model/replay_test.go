@@ -0,0 +1,69 @@
+package model
+
+import (
+	"encoding/json"
+	"goMahjong/rules"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayRebuildsFinishedHand(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		room := newTestRoom(t, 4)
+		var saved *Replay
+		room.OnHandFinished = func(replay *Replay) error {
+			saved = replay
+			return nil
+		}
+
+		require.NoError(t, room.StartGameBy(room.Owner.ID))
+		for steps := 0; room.GameState == GameStatePlaying; steps++ {
+			require.Less(t, steps, 1000, "对局没有结束")
+			step(t, room, rng)
+		}
+		require.NotNil(t, saved, "对局结束时应该保存回放")
+
+		// 回放文件经过JSON编解码后仍然能重放出同样的结果
+		data, err := json.Marshal(saved)
+		require.NoError(t, err)
+		var replay Replay
+		require.NoError(t, json.Unmarshal(data, &replay))
+		assert.Equal(t, ReplayVersion, replay.Version)
+		assert.Equal(t, RulesetSichuanSingleWin, replay.Ruleset.Name)
+
+		rebuilt, err := replay.Rebuild()
+		require.NoError(t, err)
+		assert.Equal(t, GameStateFinished, rebuilt.GameState)
+		for _, p := range room.Players {
+			assert.Equal(t, p.Score, replay.FinalScores[p.ID])
+			assert.Equal(t, p.Score, rebuilt.GetPlayer(p.ID).Score)
+		}
+
+		// 种子决定了发牌结果
+		assert.Equal(t, replay.Events[0].Deal, NewDeal(replay.Seats, replay.Seed))
+	}
+}
+
+func TestNewDealUsesEveryTileOnce(t *testing.T) {
+	seats := []SeatRecord{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	deal := NewDeal(seats, 42)
+
+	all := append([]string(nil), deal.Wall...)
+	for _, seat := range seats {
+		hand := deal.Hands[seat.ID]
+		if seat.ID == deal.Dealer {
+			assert.Len(t, hand, 14)
+		} else {
+			assert.Len(t, hand, 13)
+		}
+		all = append(all, hand...)
+	}
+	require.Len(t, all, rules.WallSize)
+	for i := 0; i < rules.TileKinds; i++ {
+		assert.Equal(t, 4, rules.Count(all, rules.TileAt(i)), rules.TileAt(i))
+	}
+}
@@ -2,10 +2,14 @@ package model
 
 import (
 	"goMahjong/config"
+	"goMahjong/rules"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // GameState 游戏状态
@@ -17,10 +21,24 @@ const (
 	GameStateFinished GameState = "finished" // 游戏结束
 )
 
+// Phase 对局中的阶段
+type Phase string
+
+const (
+	PhaseNone    Phase = ""        // 不在对局中
+	PhaseDingque Phase = "dingque" // 等待所有人定缺
+	PhaseDiscard Phase = "discard" // 等待当前玩家出牌、自摸或杠
+	PhaseClaim   Phase = "claim"   // 等待其他玩家响应打出的牌
+	PhaseDraw    Phase = "draw"    // 即将摸牌，只在两个事件之间短暂出现
+)
+
+// MaxPlayers 房间最多的玩家数，包括机器人
+const MaxPlayers = 4
+
 // Room 表示一个麻将房间，当成数据库的逻辑操作
 type Room struct {
 	ID                 string    `json:"id"`
-	Password           string    `json:"-"`
+	PasswordHash       []byte    `json:"-"` // bcrypt后的房间密码，为空表示无密码
 	Players            []*Player `json:"players"`
 	Owner              *Player   `json:"owner"`
 	GameState          GameState `json:"gameState"`
@@ -28,17 +46,107 @@ type Room struct {
 	DiscardedTiles     []string  `json:"discardedTiles"`     // 弃牌堆
 	CurrentPlayerIndex int       `json:"currentPlayerIndex"` // 当前玩家索引
 	LastPlayedTile     string    `json:"lastPlayedTile"`     // 最后打出的牌
+	Phase              Phase     `json:"phase"`              // 对局阶段
+	DealerIndex        int       `json:"dealerIndex"`        // 庄家索引
+	Events             []Event   `json:"-"`                  // 事件日志，只追加
+
+	// Transport 投递房间消息的传输层，为空时消息留在发件箱中，由调用方通过 TakeOutbound 或 Execute 取走
+	Transport Transport `json:"-"`
+
+	// Clock 计时使用的时钟，为空时不计时
+	Clock Clock `json:"-"`
+	// Timing 各阶段的时间限制
+	Timing Timing `json:"-"`
+
+	// OnHandFinished 每局结算后调用，用来保存回放和对局结果，为空时不保存
+	OnHandFinished func(*Replay) error `json:"-"`
+
+	claim            *claimWindow // 等待响应的出牌
+	lastDrawn        string       // 当前玩家刚摸到的牌，碰牌之后为空，不能自摸
+	afterGang        bool         // 当前玩家是杠后补的牌
+	discardAfterGang bool         // 最后打出的牌是杠后打出的
+
+	timer       Timer         // 当前阶段的计时器，超时由服务端代替玩家操作
+	timerGen    uint64        // 计时器的代数，重新计时后旧计时器的触发会被忽略
+	timerStart  time.Time     // 当前阶段开始计时的时间
+	timerLimit  time.Duration // 当前阶段的基础时间，不包括时间银行
+	timerPlayer *Player       // 出牌阶段正在计时的玩家，超出基础时间的部分从他的时间银行扣除
+	deadline    time.Time     // 当前阶段超时的时间，包括时间银行
+
+	seq     uint64     // 房间消息序号，每次广播加一
+	turnSeq uint64     // 最近一次轮转消息的序号，早于它的出牌请求视为过期
+	history []delivery // 最近的广播记录，用于断档补发
+	outbox  []Outbound // 没有传输层时还没取走的消息
+
+	spectators []*Player // 观战者，不占座位，只收到公开的消息
+
+	// 房间内的状态会被多个连接协程和计时器同时访问，调用房间方法前需要先加锁
+	mutex sync.Mutex
 }
 
-// NewRoom 创建一个新房间
-func NewRoom(password string) *Room {
+// NewRoom 创建一个新房间，密码只保存bcrypt哈希
+func NewRoom(password string) (*Room, error) {
+	var passwordHash []byte
+	if password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		passwordHash = hash
+	}
+
 	return &Room{
 		ID:             uuid.New().String()[:6], // 生成6位房间号
-		Password:       password,
+		PasswordHash:   passwordHash,
 		Players:        make([]*Player, 0),
 		GameState:      GameStateWaiting,
 		DiscardedTiles: make([]string, 0),
+	}, nil
+}
+
+// restoreSeqGap 恢复房间时消息序号向前跳过的数量
+// 检查点之后、服务停止之前可能还发出过消息，跳过一段序号让重连的客户端发现断档并改为请求完整状态，
+// 而不是把新消息当作已经收到过的旧消息丢掉
+const restoreSeqGap = 1 << 16
+
+// RestoreRoom 从检查点恢复房间，重放最近一局的事件，hand 为空表示还没有开过局
+// 恢复出的玩家都处于离线状态，等待重新连接
+func RestoreRoom(id string, passwordHash []byte, hand []Event, seq uint64) (*Room, error) {
+	room, err := RebuildRoom(id, hand)
+	if err != nil {
+		return nil, err
 	}
+	room.PasswordHash = passwordHash
+	room.seq = seq + restoreSeqGap
+	room.turnSeq = room.seq
+	if room.GameState == GameStatePlaying {
+		room.resetTimeBanks()
+		room.armTimer()
+	}
+	return room, nil
+}
+
+// HasPassword 房间是否设置了密码
+func (r *Room) HasPassword() bool {
+	return len(r.PasswordHash) > 0
+}
+
+// CheckPassword 校验房间密码，bcrypt比较是常量时间的
+func (r *Room) CheckPassword(password string) bool {
+	if !r.HasPassword() {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword(r.PasswordHash, []byte(password)) == nil
+}
+
+// Lock 锁定房间
+func (r *Room) Lock() {
+	r.mutex.Lock()
+}
+
+// Unlock 解锁房间
+func (r *Room) Unlock() {
+	r.mutex.Unlock()
 }
 
 // AddPlayer 添加玩家到房间
@@ -66,240 +174,507 @@ func (r *Room) GetPlayer(playerID string) *Player {
 	return nil
 }
 
+// Humans 返回房间中的真人玩家
+func (r *Room) Humans() []*Player {
+	humans := make([]*Player, 0, len(r.Players))
+	for _, p := range r.Players {
+		if !p.Bot {
+			humans = append(humans, p)
+		}
+	}
+	return humans
+}
+
 // SetOwner 设置房主
 func (r *Room) SetOwner(player *Player) {
 	r.Owner = player
 }
 
+// IsOwner 判断玩家是否是房主
+func (r *Room) IsOwner(playerID string) bool {
+	return r.Owner != nil && r.Owner.ID == playerID
+}
+
 // GetRoomInfo 获取房间信息
-func (r *Room) GetRoomInfo() map[string]interface{} {
-	players := make([]map[string]interface{}, 0)
+func (r *Room) GetRoomInfo() RoomInfoData {
+	players := make([]PlayerInfo, 0)
 	for _, p := range r.Players {
 		playerInfo := p.GetPublicInfo()
 		// 添加是否是房主的标识
-		playerInfo["isOwner"] = (r.Owner != nil && p.ID == r.Owner.ID)
+		playerInfo.IsOwner = r.IsOwner(p.ID)
 		players = append(players, playerInfo)
 	}
 
-	var ownerInfo map[string]interface{}
+	var ownerInfo *PlayerInfo
 	if r.Owner != nil {
-		ownerInfo = r.Owner.GetPublicInfo()
+		info := r.Owner.GetPublicInfo()
+		info.IsOwner = true
+		ownerInfo = &info
 	}
 
-	return map[string]interface{}{
-		"id":        r.ID,
-		"players":   players,
-		"owner":     ownerInfo, // 确保返回房主信息
-		"gameState": r.GameState,
+	return RoomInfoData{
+		ID:         r.ID,
+		Players:    players,
+		Owner:      ownerInfo, // 确保返回房主信息
+		GameState:  r.GameState,
+		Spectators: len(r.spectators),
 	}
 }
 
-// BroadcastAll 向房间内所有玩家广播消息
-func (r *Room) BroadcastAll(message Message) {
+// StartGame 洗牌发牌，开始新的一局
+// 庄家随机选出，从庄家开始每人13张，庄家多拿一张
+func (r *Room) StartGame() error {
+	seats := make([]SeatRecord, 0, len(r.Players))
 	for _, p := range r.Players {
-		p.SendMessage(message)
+		seats = append(seats, SeatRecord{ID: p.ID, Name: p.Name, Score: p.Score})
 	}
+	return r.StartGameWithDeal(NewDeal(seats, time.Now().UnixNano()))
 }
 
-// BroadcastExcept 向除了指定玩家外的所有玩家广播消息
-func (r *Room) BroadcastExcept(message Message, exceptPlayerID string) {
-	for _, p := range r.Players {
-		if p.ID != exceptPlayerID {
-			p.SendMessage(message)
+// StartGameWithDeal 按给定的发牌开始新的一局，自对弈和测试用固定种子的发牌重现对局
+func (r *Room) StartGameWithDeal(deal *DealDetail) error {
+	config.GetZapLogger().Info("游戏开始，房间ID: " + r.ID)
+	return r.record(Event{Type: EventDeal, Deal: deal})
+}
+
+// NewDeal 用种子洗牌、选庄并发牌，同样的座位和种子总是得到同样的发牌结果
+func NewDeal(seats []SeatRecord, seed int64) *DealDetail {
+	rng := rand.New(rand.NewSource(seed))
+	wall := rules.NewWall()
+	rng.Shuffle(len(wall), func(i, j int) {
+		wall[i], wall[j] = wall[j], wall[i]
+	})
+
+	dealer := rng.Intn(len(seats))
+	deal := &DealDetail{
+		Seed:   seed,
+		Seats:  seats,
+		Dealer: seats[dealer].ID,
+		Hands:  make(map[string][]string),
+	}
+	for i := 0; i < len(seats); i++ {
+		id := seats[(dealer+i)%len(seats)].ID
+		deal.Hands[id] = wall[:13:13]
+		wall = wall[13:]
+	}
+	deal.Hands[deal.Dealer] = append(deal.Hands[deal.Dealer], wall[0])
+	deal.Wall = wall[1:]
+	return deal
+}
+
+// playerOptions 玩家当前可以执行的动作
+func (r *Room) playerOptions(p *Player) []string {
+	if r.Phase == PhaseClaim {
+		if options := r.pendingClaimOptions(p.ID); len(options) > 0 {
+			return append(append([]string(nil), options...), ActionPass)
 		}
+		return nil
 	}
+	return r.turnOptions(p)
 }
 
-// StartGame 开始游戏
-func (r *Room) StartGame() {
-	logger := config.GetZapLogger()
-	logger.Info("游戏开始，房间ID: " + r.ID)
+// StartGameBy 由玩家发起开始游戏，只有房主可以在等待状态下开始
+func (r *Room) StartGameBy(playerID string) error {
+	if !r.IsOwner(playerID) {
+		return NewGameError(ErrCodeForbidden, "只有房主可以开始游戏")
+	}
+	if r.GameState == GameStatePlaying {
+		return NewGameError(ErrCodeInvalidState, "游戏已经开始")
+	}
+	if len(r.Players) < 2 {
+		return NewGameError(ErrCodeInvalidState, "至少需要2名玩家才能开始游戏")
+	}
 
-	r.GameState = GameStatePlaying
-	r.DiscardedTiles = make([]string, 0)
+	if err := r.StartGame(); err != nil {
+		return err
+	}
+	r.resetTimeBanks()
+	r.armTimer()
 
-	// 初始化麻将牌
-	r.initTiles()
+	// 每个玩家收到的开局消息中只有自己的手牌
+	state := r.PublicView()
+	r.BroadcastEach(MsgGameStarted, func(p *Player) interface{} {
+		return GameStartedData{GameStateData: state, Tiles: copyTiles(p.Tiles)}
+	})
+	r.turnSeq = r.seq
+	return nil
+}
 
-	// 洗牌
-	r.shuffleTiles()
+// CheckFresh 检查客户端请求所基于的序号是否已过期
+// 客户端在出牌等请求中带上自己收到的最后一个序号，轮转之后的旧请求会被拒绝；
+// 开局之后序号不会是0，不带序号的请求无法判断是否过期，一律拒绝
+func (r *Room) CheckFresh(seq uint64) error {
+	if seq == 0 {
+		return NewGameError(ErrCodeBadRequest, "请求缺少序号")
+	}
+	if seq < r.turnSeq {
+		return NewGameError(ErrCodeStaleAction, "对局状态已经变化，请求已过期")
+	}
+	return nil
+}
 
-	// 发牌
-	r.dealTiles()
+// HandleDingque 处理定缺，所有人定缺后公布结果并轮到庄家出牌
+func (r *Room) HandleDingque(playerID string, suit string) error {
+	if r.GameState != GameStatePlaying || r.Phase != PhaseDingque {
+		return NewGameError(ErrCodeInvalidState, "现在不是定缺阶段")
+	}
+	player := r.GetPlayer(playerID)
+	if player == nil {
+		return NewGameError(ErrCodeForbidden, "玩家不在房间中")
+	}
+	if player.Missing != "" {
+		return NewGameError(ErrCodeInvalidState, "已经定缺了")
+	}
 
-	// 随机选择第一个玩家
-	r.CurrentPlayerIndex = rand.Intn(len(r.Players))
+	if err := r.record(Event{Type: EventDingque, PlayerID: playerID, Suit: suit}); err != nil {
+		return err
+	}
+	if r.Phase == PhaseDingque {
+		return nil
+	}
 
-	// 通知每个玩家他们的手牌
+	result := DingqueData{Suits: make(map[string]string)}
 	for _, p := range r.Players {
-		p.SendMessage(Message{
-			Type: "your_tiles",
-			Data: map[string]interface{}{
-				"tiles": p.Tiles,
-			},
-		})
+		result.Suits[p.ID] = p.Missing
 	}
+	r.BroadcastAll(Message{Type: MsgDingque, Data: result})
+	r.promptTurn()
+	return nil
 }
 
-// 初始化麻将牌
-func (r *Room) initTiles() {
-	// 四川麻将使用的牌：条、筒、万（1-9各4张）共108张
-	tileTypes := []string{
-		// 条子
-		"1t", "2t", "3t", "4t", "5t", "6t", "7t", "8t", "9t",
-		// 筒子
-		"1p", "2p", "3p", "4p", "5p", "6p", "7p", "8p", "9p",
-		// 万子
-		"1w", "2w", "3w", "4w", "5w", "6w", "7w", "8w", "9w",
+// HandlePlayTile 处理玩家出牌
+func (r *Room) HandlePlayTile(playerID string, tile string) error {
+	logger := config.GetZapLogger()
+
+	if r.GameState != GameStatePlaying {
+		return NewGameError(ErrCodeInvalidState, "游戏尚未开始")
+	}
+	if r.Phase != PhaseDiscard {
+		return NewGameError(ErrCodeInvalidState, "现在不能出牌")
 	}
 
-	r.Tiles = make([]string, 0)
+	// 检查是否是当前玩家的回合
+	if r.Players[r.CurrentPlayerIndex].ID != playerID {
+		return NewGameError(ErrCodeNotYourTurn, "还没有轮到你出牌")
+	}
 
-	// 每种牌4张
-	for _, t := range tileTypes {
-		for i := 0; i < 4; i++ {
-			r.Tiles = append(r.Tiles, t)
-		}
+	player := r.GetPlayer(playerID)
+	if player == nil {
+		return NewGameError(ErrCodeForbidden, "玩家不在房间中")
 	}
-}
 
-// 洗牌
-func (r *Room) shuffleTiles() {
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(r.Tiles), func(i, j int) {
-		r.Tiles[i], r.Tiles[j] = r.Tiles[j], r.Tiles[i]
+	// 检查玩家是否有这张牌
+	if rules.Count(player.Tiles, tile) == 0 {
+		return NewGameError(ErrCodeInvalidTile, "手牌中没有 "+tile)
+	}
+	// 缺门的牌要先打完
+	if rules.Suit(tile) != player.Missing && rules.CountSuit(player.Tiles, player.Missing) > 0 {
+		return NewGameError(ErrCodeInvalidTile, "需要先打完缺门的牌")
+	}
+
+	if err := r.record(Event{Type: EventDiscard, PlayerID: playerID, Tile: tile}); err != nil {
+		return err
+	}
+	logger.Info("玩家 " + player.Name + " 打出了 " + tile)
+
+	// 广播出牌信息
+	r.BroadcastAll(Message{
+		Type: MsgTilePlayed,
+		Data: TilePlayedData{PlayerID: playerID, Tile: tile},
 	})
-}
 
-// 发牌
-func (r *Room) dealTiles() {
-	// 每个玩家13张牌
-	for _, p := range r.Players {
-		p.Tiles = make([]string, 0)
-		for i := 0; i < 13; i++ {
-			if len(r.Tiles) > 0 {
-				p.Tiles = append(p.Tiles, r.Tiles[0])
-				r.Tiles = r.Tiles[1:]
+	// 有人可以碰杠胡时等待他们的选择，否则轮到下一个玩家
+	// 提示只发给能碰杠胡的人，不占用序号，其他人收到的消息和没有人能碰杠胡时一样
+	if r.Phase == PhaseClaim {
+		r.armTimer()
+		for _, p := range r.Players {
+			if prompt, ok := r.claimPrompt(p.ID); ok {
+				r.SendDirect(p, prompt)
 			}
 		}
+		r.turnSeq = r.seq
+		return nil
 	}
+	return r.drawFor((r.CurrentPlayerIndex + 1) % len(r.Players))
+}
 
-	// 给当前玩家额外发一张牌
-	if len(r.Tiles) > 0 {
-		r.Players[r.CurrentPlayerIndex].Tiles = append(
-			r.Players[r.CurrentPlayerIndex].Tiles,
-			r.Tiles[0],
-		)
-		r.Tiles = r.Tiles[1:]
+// HandlePlayerAction 处理玩家动作（碰、杠、胡、过）
+func (r *Room) HandlePlayerAction(playerID string, action string, tiles []string) error {
+	if r.GameState != GameStatePlaying {
+		return NewGameError(ErrCodeInvalidState, "游戏尚未开始")
+	}
+	player := r.GetPlayer(playerID)
+	if player == nil {
+		return NewGameError(ErrCodeForbidden, "玩家不在房间中")
 	}
+
+	switch r.Phase {
+	case PhaseClaim:
+		return r.respondClaim(player, action)
+	case PhaseDiscard:
+		if r.Players[r.CurrentPlayerIndex].ID != playerID {
+			return NewGameError(ErrCodeNotYourTurn, "还没有轮到你")
+		}
+		switch action {
+		case ActionHu:
+			if !contains(r.turnOptions(player), ActionHu) {
+				return NewGameError(ErrCodeInvalidState, "还不能胡牌")
+			}
+			return r.win(player, r.lastDrawn, "")
+		case ActionGang:
+			if len(tiles) != 1 {
+				return NewGameError(ErrCodeBadRequest, "需要指定要杠的牌")
+			}
+			return r.selfGang(player, tiles[0])
+		}
+	}
+	return NewGameError(ErrCodeInvalidState, "现在不能执行 "+action)
 }
 
-// GetGameState 获取游戏状态
-func (r *Room) GetGameState() map[string]interface{} {
-	playerInfos := make([]map[string]interface{}, 0)
-	for _, p := range r.Players {
-		playerInfos = append(playerInfos, map[string]interface{}{
-			"id":        p.ID,
-			"name":      p.Name,
-			"score":     p.Score,
-			"tileCount": len(p.Tiles),
-		})
+// respondClaim 记录玩家对出牌的选择，所有人都选择后再决定由谁碰杠胡
+func (r *Room) respondClaim(player *Player, action string) error {
+	options := r.pendingClaimOptions(player.ID)
+	if len(options) == 0 {
+		return NewGameError(ErrCodeInvalidState, "没有可以执行的动作")
+	}
+	if action != ActionPass && !contains(options, action) {
+		return NewGameError(ErrCodeInvalidState, "不能执行 "+action)
 	}
 
-	return map[string]interface{}{
-		"gameState":          r.GameState,
-		"players":            playerInfos,
-		"currentPlayerIndex": r.CurrentPlayerIndex,
-		"currentPlayerID":    r.Players[r.CurrentPlayerIndex].ID,
-		"discardedTiles":     r.DiscardedTiles,
-		"remainingTiles":     len(r.Tiles),
+	r.claim.responses[player.ID] = action
+	if len(r.claim.responses) < len(r.claim.options) {
+		return nil
 	}
+	return r.resolveClaims()
 }
 
-// HandlePlayTile 处理玩家出牌
-func (r *Room) HandlePlayTile(playerID string, tile string) {
-	logger := config.GetZapLogger()
+// resolveClaims 胡优先于杠和碰，多人胡同一张牌时离出牌者最近的下家胡
+func (r *Room) resolveClaims() error {
+	w := r.claim
+	order := r.claimOrder(w.from)
 
-	// 检查是否是当前玩家的回合
-	if r.Players[r.CurrentPlayerIndex].ID != playerID {
-		return
+	for _, p := range order {
+		if w.responses[p.ID] == ActionHu {
+			return r.win(p, w.tile, w.from)
+		}
 	}
-
-	player := r.GetPlayer(playerID)
-	if player == nil {
-		return
+	for _, p := range order {
+		switch w.responses[p.ID] {
+		case ActionGang:
+			if err := r.record(Event{Type: EventGang, PlayerID: p.ID, Tile: w.tile, From: w.from, Kind: rules.MeldMingGang}); err != nil {
+				return err
+			}
+			r.broadcastMeld(p, rules.MeldMingGang, w.tile, w.from)
+			return r.drawFor(r.seatIndex(p.ID))
+		case ActionPeng:
+			if err := r.record(Event{Type: EventClaim, PlayerID: p.ID, Tile: w.tile, From: w.from}); err != nil {
+				return err
+			}
+			r.broadcastMeld(p, rules.MeldPeng, w.tile, w.from)
+			r.promptTurn()
+			return nil
+		}
 	}
 
-	// 检查玩家是否有这张牌
-	tileIndex := -1
-	for i, t := range player.Tiles {
-		if t == tile {
-			tileIndex = i
-			break
-		}
+	// 都选择过，轮到出牌者的下家
+	return r.drawFor((r.seatIndex(w.from) + 1) % len(r.Players))
+}
+
+// selfGang 在自己的回合暗杠或补杠，然后补一张牌
+func (r *Room) selfGang(player *Player, tile string) error {
+	if !contains(r.gangTiles(player), tile) || len(r.Tiles) == 0 {
+		return NewGameError(ErrCodeInvalidTile, "不能杠 "+tile)
 	}
 
-	if tileIndex == -1 {
-		return
+	kind := rules.MeldAnGang
+	if player.findPeng(tile) != nil {
+		kind = rules.MeldBuGang
+	}
+	if err := r.record(Event{Type: EventGang, PlayerID: player.ID, Tile: tile, Kind: kind}); err != nil {
+		return err
 	}
+	r.broadcastMeld(player, kind, tile, "")
+	return r.drawFor(r.seatIndex(player.ID))
+}
 
-	// 从玩家手牌中移除这张牌
-	player.Tiles = append(player.Tiles[:tileIndex], player.Tiles[tileIndex+1:]...)
+// broadcastMeld 广播碰杠，暗杠的牌面只有本人能看到
+func (r *Room) broadcastMeld(player *Player, kind, tile, from string) {
+	r.BroadcastEach(MsgMeld, func(p *Player) interface{} {
+		data := MeldData{PlayerID: player.ID, Kind: kind, Tile: tile, From: from}
+		if kind == rules.MeldAnGang && p.ID != player.ID {
+			data.Tile = ""
+		}
+		return data
+	})
+}
 
-	// 添加到弃牌堆
-	r.DiscardedTiles = append(r.DiscardedTiles, tile)
-	r.LastPlayedTile = tile
+// drawFor 让某个座位的玩家摸一张牌，牌摸完了就流局
+func (r *Room) drawFor(seat int) error {
+	if len(r.Tiles) == 0 {
+		return r.settle(nil)
+	}
 
-	logger.Info("玩家 " + player.Name + " 打出了 " + tile)
+	drawer := r.Players[seat]
+	newTile := r.Tiles[0]
+	if err := r.record(Event{Type: EventDraw, PlayerID: drawer.ID, Tile: newTile}); err != nil {
+		return err
+	}
 
-	// 广播出牌信息
-	r.BroadcastAll(Message{
-		Type: "tile_played",
-		Data: map[string]interface{}{
-			"playerID": playerID,
-			"tile":     tile,
-		},
+	// 通知所有人有玩家摸牌，只有摸牌的玩家能看到是哪张
+	r.BroadcastEach(MsgNewTile, func(p *Player) interface{} {
+		data := NewTileData{PlayerID: drawer.ID}
+		if p.ID == drawer.ID {
+			data.Tile = newTile
+		}
+		return data
 	})
+	r.promptTurn()
+	return nil
+}
 
-	// TODO: 检查其他玩家是否可以吃碰杠胡
+// promptTurn 通知所有玩家轮到谁了和出牌的时限，当前玩家同时收到可以自摸或杠的提示
+func (r *Room) promptTurn() {
+	current := r.Players[r.CurrentPlayerIndex]
+	options := r.turnOptions(current)
+	r.armTimer()
+	r.BroadcastEach(MsgTurnChanged, func(p *Player) interface{} {
+		data := TurnChangedData{PlayerID: current.ID}
+		if r.timer != nil {
+			data.Seconds, data.TimeBank = seconds(r.timerLimit), seconds(current.timeBank)
+		}
+		if p.ID == current.ID {
+			data.Options = options
+		}
+		return data
+	})
+	r.turnSeq = r.seq
+}
 
-	// 轮到下一个玩家
-	r.nextPlayer()
+// win 胡牌并结算，from为点炮的玩家，自摸时为空
+func (r *Room) win(player *Player, tile string, from string) error {
+	fan, patterns := r.winFan(player, tile, from)
+	e := Event{
+		Type:     EventWin,
+		PlayerID: player.ID,
+		Tile:     tile,
+		From:     from,
+		Win:      &WinDetail{SelfDrawn: from == "", Fan: fan, Patterns: patterns},
+	}
+	if err := r.record(e); err != nil {
+		return err
+	}
+	return r.settle(&e)
 }
 
-// 轮到下一个玩家
-func (r *Room) nextPlayer() {
-	r.CurrentPlayerIndex = (r.CurrentPlayerIndex + 1) % len(r.Players)
+// winFan 玩家胡这张牌的番数和番型，from为点炮的玩家，自摸时为空，调用时还没有记录胡牌事件
+func (r *Room) winFan(player *Player, tile string, from string) (int, []string) {
+	hand := append([]string(nil), player.Tiles...)
+	afterGang := r.afterGang
+	if from != "" {
+		hand = append(hand, tile)
+		afterGang = r.discardAfterGang
+	}
+	return rules.Fan(rules.WinContext{
+		Hand:      hand,
+		Melds:     player.Melds,
+		SelfDrawn: from == "",
+		AfterGang: afterGang,
+		LastTile:  len(r.Tiles) == 0,
+	})
+}
 
-	// 给下一个玩家发一张牌
-	if len(r.Tiles) > 0 {
-		newTile := r.Tiles[0]
-		r.Tiles = r.Tiles[1:]
-		r.Players[r.CurrentPlayerIndex].Tiles = append(r.Players[r.CurrentPlayerIndex].Tiles, newTile)
+// settle 结算本局并公布结果，win为nil表示流局
+func (r *Room) settle(win *Event) error {
+	logger := config.GetZapLogger()
+	r.StopTimer()
+
+	deltas := r.settlementDeltas(win, viper.GetInt("game.baseScore"), viper.GetInt("game.maxFan"))
+	if err := r.record(Event{Type: EventSettlement, Settlement: &SettlementDetail{Deltas: deltas}}); err != nil {
+		return err
+	}
+
+	result := GameOverData{
+		Scores:   make(map[string]int),
+		Deltas:   deltas,
+		Patterns: make([]string, 0),
+		Hands:    r.RevealView(),
+	}
+	for _, p := range r.Players {
+		result.Scores[p.ID] = p.Score
+	}
+	if win != nil {
+		winner := r.GetPlayer(win.PlayerID)
+		info := winner.GetPublicInfo()
+		info.IsOwner = r.IsOwner(winner.ID)
+		result.Winner = &info
+		result.WinningTile = win.Tile
+		result.DiscarderID = win.From
+		result.SelfDrawn = win.Win.SelfDrawn
+		result.Fan = win.Win.Fan
+		result.Patterns = win.Win.Patterns
+		logger.Info("房间 " + r.ID + " 本局结束，" + winner.Name + " 胡牌")
+	} else {
+		logger.Info("房间 " + r.ID + " 本局流局")
+	}
 
-		// 通知玩家新抽到的牌
-		r.Players[r.CurrentPlayerIndex].SendMessage(Message{
-			Type: "new_tile",
-			Data: map[string]interface{}{
-				"tile": newTile,
-			},
-		})
+	if r.OnHandFinished != nil {
+		replay, err := NewReplay(r)
+		if err == nil {
+			err = r.OnHandFinished(replay)
+		}
+		if err != nil {
+			logger.Error("房间 " + r.ID + " 保存回放失败: " + err.Error())
+		} else {
+			result.ReplayID = replay.ID
+		}
 	}
 
-	// 通知所有玩家轮到谁了
 	r.BroadcastAll(Message{
-		Type: "turn_changed",
-		Data: map[string]interface{}{
-			"playerID": r.Players[r.CurrentPlayerIndex].ID,
-		},
+		Type: MsgGameOver,
+		Data: result,
 	})
+	return nil
 }
 
-// HandlePlayerAction 处理玩家动作（吃、碰、杠、胡）
-func (r *Room) HandlePlayerAction(playerID string, actionType string, tiles []interface{}) {
-	// 简化版实现，实际麻将规则更复杂
-	logger := config.GetZapLogger()
-	logger.Info("玩家 " + playerID + " 执行动作: " + actionType)
+// settlementDeltas 按规则算出本局每个人的输赢，win为nil表示流局
+// 胡牌：点炮者付给胡牌者，自摸时其他人都付；杠：暗杠其他人各付2倍底分，补杠各付1倍，明杠由点杠者付2倍
+func (r *Room) settlementDeltas(win *Event, base, maxFan int) map[string]int {
+	deltas := make(map[string]int)
+	for _, p := range r.Players {
+		deltas[p.ID] += 0
+	}
+	pay := func(from, to string, points int) {
+		if from == to || r.GetPlayer(from) == nil {
+			return
+		}
+		deltas[from] -= points
+		deltas[to] += points
+	}
+	payByOthers := func(to string, points int) {
+		for _, p := range r.Players {
+			pay(p.ID, to, points)
+		}
+	}
 
-	// TODO: 实现吃碰杠胡的逻辑
+	for _, p := range r.Players {
+		for _, m := range p.Melds {
+			switch m.Kind {
+			case rules.MeldAnGang:
+				payByOthers(p.ID, 2*base)
+			case rules.MeldBuGang:
+				payByOthers(p.ID, base)
+			case rules.MeldMingGang:
+				pay(m.From, p.ID, 2*base)
+			}
+		}
+	}
+	if win != nil {
+		points := rules.Points(base, win.Win.Fan, maxFan)
+		if win.From == "" {
+			payByOthers(win.PlayerID, points)
+		} else {
+			pay(win.From, win.PlayerID, points)
+		}
+	}
+	return deltas
 }
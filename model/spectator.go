@@ -0,0 +1,100 @@
+package model
+
+import "github.com/spf13/viper"
+
+// 观战者不占座位，不参与对局，只能收到公开的消息：观战者不是座位上的玩家，
+// 按收件人生成消息时自然得到和其他玩家一样的公开版本（摸到的牌、暗杠、可执行的动作都隐去）
+// 教练是特殊的观战者，每个事件之后所有人的手牌会在 Timing.CoachDelay 之后发给教练，延迟公开避免向座位上的人报牌
+
+// AddSpectator 添加观战者，人数已满或不允许教练观战时返回 GameError
+func (r *Room) AddSpectator(spectator *Player) error {
+	if len(r.spectators) >= viper.GetInt("game.maxSpectators") {
+		return NewGameError(ErrCodeInvalidState, "观战人数已满")
+	}
+	if spectator.Coach && (r.Clock == nil || r.Timing.CoachDelay <= 0) {
+		return NewGameError(ErrCodeForbidden, "这个服务器不允许教练观战")
+	}
+	r.spectators = append(r.spectators, spectator)
+	return nil
+}
+
+// RemoveSpectator 移除观战者
+func (r *Room) RemoveSpectator(spectatorID string) {
+	for i, s := range r.spectators {
+		if s.ID == spectatorID {
+			r.spectators = append(r.spectators[:i], r.spectators[i+1:]...)
+			break
+		}
+	}
+}
+
+// GetSpectator 根据ID获取观战者
+func (r *Room) GetSpectator(spectatorID string) *Player {
+	for _, s := range r.spectators {
+		if s.ID == spectatorID {
+			return s
+		}
+	}
+	return nil
+}
+
+// Spectators 返回房间中的观战者
+func (r *Room) Spectators() []*Player {
+	return r.spectators
+}
+
+// BroadcastSpectators 通知所有人观战人数变化
+func (r *Room) BroadcastSpectators() {
+	r.BroadcastAll(Message{Type: MsgSpectators, Data: SpectatorsData{Count: len(r.spectators)}})
+}
+
+// Recipients 房间消息的收件人：座位上的玩家和观战者
+func (r *Room) Recipients() []*Player {
+	recipients := make([]*Player, 0, len(r.Players)+len(r.spectators))
+	recipients = append(recipients, r.Players...)
+	return append(recipients, r.spectators...)
+}
+
+// scheduleCoachView 记下当前所有人的手牌，延迟之后发给教练
+// 每个事件之后调用，没有教练时什么都不做
+func (r *Room) scheduleCoachView() {
+	delay := r.Timing.CoachDelay
+	if r.Clock == nil || delay <= 0 || !r.hasCoach() || r.GameState != GameStatePlaying {
+		return
+	}
+
+	view := CoachViewData{
+		Hands:           make([]RevealedHand, 0, len(r.Players)),
+		Missing:         make(map[string]string),
+		Phase:           r.Phase,
+		CurrentPlayerID: r.Players[r.CurrentPlayerIndex].ID,
+		Delay:           seconds(delay),
+	}
+	for _, p := range r.Players {
+		view.Hands = append(view.Hands, RevealedHand{PlayerID: p.ID, Tiles: copyTiles(p.Tiles)})
+		if p.Missing != "" {
+			view.Missing[p.ID] = p.Missing
+		}
+	}
+
+	r.Clock.AfterFunc(delay, func() {
+		r.Lock()
+		defer r.Unlock()
+		// 发送时还在观战的教练才能收到
+		for _, s := range r.spectators {
+			if s.Coach {
+				r.SendDirect(s, Message{Type: MsgCoachView, Data: view})
+			}
+		}
+	})
+}
+
+// hasCoach 房间中是否有教练
+func (r *Room) hasCoach() bool {
+	for _, s := range r.spectators {
+		if s.Coach {
+			return true
+		}
+	}
+	return false
+}
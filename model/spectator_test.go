@@ -0,0 +1,112 @@
+package model
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 观战者收到和座位上的玩家同样多的房间消息，但都是公开的版本
+func TestSpectatorsReceiveOnlyPublicMessages(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		room := newTestRoom(t, 2+int(seed%3))
+		spectator := NewPlayer("观众")
+		require.NoError(t, room.AddSpectator(spectator))
+		require.NoError(t, room.StartGameBy(room.Owner.ID))
+
+		seqs := make(map[string][]uint64)
+		for steps := 0; ; steps++ {
+			require.Less(t, steps, 1000, "对局没有结束")
+			for _, out := range room.TakeOutbound() {
+				// 碰杠胡的提示只发给能操作的玩家
+				if out.Message.Type != MsgClaimWindow {
+					seqs[out.PlayerID] = append(seqs[out.PlayerID], out.Message.Seq)
+				}
+				if out.PlayerID == spectator.ID {
+					checkMessage(t, room, spectator, out.Message)
+				}
+			}
+			if room.GameState != GameStatePlaying {
+				break
+			}
+			step(t, room, rng)
+		}
+		assert.Equal(t, seqs[room.Owner.ID], seqs[spectator.ID], "观战者没有错过任何广播")
+	}
+}
+
+// 观战者不能参与对局，也不占座位
+func TestSpectatorsCannotPlay(t *testing.T) {
+	room := newTestRoom(t, 2)
+	spectator := NewPlayer("观众")
+	require.NoError(t, room.AddSpectator(spectator))
+	assert.Len(t, room.Players, 2)
+	assert.Equal(t, 1, room.GetRoomInfo().Spectators)
+
+	_, err := room.Execute(StartGameCommand{PlayerID: spectator.ID})
+	assert.Error(t, err)
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+	_, err = room.Execute(DingqueCommand{PlayerID: spectator.ID, Suit: "t"})
+	assert.Error(t, err)
+	assert.Nil(t, room.GetPlayer(spectator.ID))
+
+	room.RemoveSpectator(spectator.ID)
+	assert.Nil(t, room.GetSpectator(spectator.ID))
+	assert.Empty(t, room.Spectators())
+}
+
+func TestSpectatorLimits(t *testing.T) {
+	viper.Set("game.maxSpectators", 1)
+	t.Cleanup(func() { viper.Set("game.maxSpectators", nil) })
+
+	room, _ := timedRoom(t, 2, Timing{})
+	coach := NewPlayer("教练")
+	coach.Coach = true
+	assert.Error(t, room.AddSpectator(coach), "没有设置延迟时不允许教练观战")
+	require.NoError(t, room.AddSpectator(NewPlayer("观众")))
+	assert.Error(t, room.AddSpectator(NewPlayer("观众")), "观战人数已满")
+}
+
+// 教练在延迟之后才收到所有人的手牌，普通观战者收不到
+func TestCoachSeesAllHandsAfterTheDelay(t *testing.T) {
+	room, clock := timedRoom(t, 3, Timing{CoachDelay: 500 * time.Millisecond})
+	coach := NewPlayer("教练")
+	coach.Coach = true
+	spectator := NewPlayer("观众")
+	room.Lock()
+	require.NoError(t, room.AddSpectator(coach))
+	require.NoError(t, room.AddSpectator(spectator))
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+	dealt := make(map[string][]string)
+	for _, p := range room.Players {
+		dealt[p.ID] = append([]string(nil), p.Tiles...)
+	}
+	assert.Empty(t, outboundFor(room.TakeOutbound(), coach.ID, MsgCoachView), "开局时还看不到手牌")
+	room.Unlock()
+
+	clock.Advance(400 * time.Millisecond)
+	room.Lock()
+	assert.Empty(t, outboundFor(room.TakeOutbound(), coach.ID, MsgCoachView), "延迟之前看不到手牌")
+	room.Unlock()
+
+	clock.Advance(100 * time.Millisecond)
+	room.Lock()
+	out := room.TakeOutbound()
+	room.Unlock()
+	assert.Empty(t, outboundFor(out, spectator.ID, MsgCoachView))
+	views := outboundFor(out, coach.ID, MsgCoachView)
+	require.NotEmpty(t, views)
+
+	view := views[0].Data.(CoachViewData)
+	assert.Equal(t, PhaseDingque, view.Phase)
+	assert.Equal(t, 1, view.Delay)
+	require.Len(t, view.Hands, 3)
+	for _, hand := range view.Hands {
+		assert.Equal(t, dealt[hand.PlayerID], hand.Tiles)
+	}
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"goMahjong/config"
+	"goMahjong/rules"
+	"time"
+)
+
+// 对局计时：定缺、出牌、响应出牌都有时间限制，超时由服务端代替玩家操作，一个人挂机不会卡住整个房间
+// 出牌超时之前先用玩家的时间银行，超出基础时间的部分从时间银行扣除，时间银行每局重置
+
+// phaseLimit 当前阶段的基础时间限制，0 表示不计时
+func (r *Room) phaseLimit() time.Duration {
+	if r.Clock == nil {
+		return 0
+	}
+	switch r.Phase {
+	case PhaseDingque:
+		return r.Timing.Dingque
+	case PhaseDiscard:
+		return r.Timing.Turn
+	case PhaseClaim:
+		return r.Timing.Claim
+	}
+	return 0
+}
+
+// resetTimeBanks 开局时重置所有人的时间银行
+func (r *Room) resetTimeBanks() {
+	for _, p := range r.Players {
+		p.timeBank = r.Timing.TimeBank
+	}
+}
+
+// armTimer 进入新阶段时重新计时，在广播这个阶段的消息之前调用，消息中才能带上剩余时间
+func (r *Room) armTimer() {
+	r.StopTimer()
+	limit := r.phaseLimit()
+	if limit <= 0 {
+		return
+	}
+
+	total := limit
+	if r.Phase == PhaseDiscard {
+		r.timerPlayer = r.Players[r.CurrentPlayerIndex]
+		total += r.timerPlayer.timeBank
+	}
+	r.timerStart = r.Clock.Now()
+	r.timerLimit = limit
+	r.deadline = r.timerStart.Add(total)
+
+	gen := r.timerGen
+	r.timer = r.Clock.AfterFunc(total, func() {
+		r.Lock()
+		defer r.Unlock()
+		// 计时器触发时这个阶段可能刚好结束
+		if gen != r.timerGen || r.GameState != GameStatePlaying {
+			return
+		}
+		r.timer = nil
+		r.onTimeout()
+	})
+}
+
+// StopTimer 停止计时，出牌阶段超出基础时间的部分从当前玩家的时间银行扣除
+// 房间关闭时也要调用，避免计时器继续替已经离开的玩家操作
+func (r *Room) StopTimer() {
+	r.timerGen++
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	if p := r.timerPlayer; p != nil {
+		if over := r.Clock.Now().Sub(r.timerStart) - r.timerLimit; over > 0 {
+			p.timeBank -= over
+			if p.timeBank < 0 {
+				p.timeBank = 0
+			}
+		}
+		r.timerPlayer = nil
+	}
+}
+
+// timeLeft 当前阶段剩余的时间，包括时间银行，不计时时为0
+func (r *Room) timeLeft() time.Duration {
+	if r.timer == nil {
+		return 0
+	}
+	if left := r.deadline.Sub(r.Clock.Now()); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// onTimeout 超时后代替玩家操作：定缺手里最少的花色，出牌打出刚摸的牌或缺门的牌，响应出牌一律选择过
+func (r *Room) onTimeout() {
+	logger := config.GetZapLogger()
+
+	var err error
+	switch r.Phase {
+	case PhaseDingque:
+		for _, p := range r.Players {
+			if r.Phase != PhaseDingque {
+				break
+			}
+			if p.Missing == "" {
+				logger.Info("玩家 " + p.Name + " 定缺超时")
+				if err = r.HandleDingque(p.ID, fewestSuit(p.Tiles)); err != nil {
+					break
+				}
+			}
+		}
+	case PhaseDiscard:
+		current := r.Players[r.CurrentPlayerIndex]
+		current.timeBank = 0
+		r.timerPlayer = nil
+		logger.Info("玩家 " + current.Name + " 出牌超时")
+		err = r.HandlePlayTile(current.ID, r.timeoutDiscard(current))
+	case PhaseClaim:
+		for _, p := range r.claimOrder(r.claim.from) {
+			if r.Phase != PhaseClaim {
+				break
+			}
+			if len(r.pendingClaimOptions(p.ID)) > 0 {
+				logger.Info("玩家 " + p.Name + " 响应超时，自动过")
+				if err = r.respondClaim(p, ActionPass); err != nil {
+					break
+				}
+			}
+		}
+	}
+	if err != nil {
+		logger.Error("房间 " + r.ID + " 超时处理失败: " + err.Error())
+	}
+}
+
+// timeoutDiscard 超时自动打出的牌：有缺门的牌先打缺门，否则打刚摸到的牌，碰牌之后打最后一张
+func (r *Room) timeoutDiscard(p *Player) string {
+	if rules.CountSuit(p.Tiles, p.Missing) > 0 {
+		if rules.Suit(r.lastDrawn) == p.Missing && rules.Count(p.Tiles, r.lastDrawn) > 0 {
+			return r.lastDrawn
+		}
+		for _, tile := range p.Tiles {
+			if rules.Suit(tile) == p.Missing {
+				return tile
+			}
+		}
+	}
+	if r.lastDrawn != "" && rules.Count(p.Tiles, r.lastDrawn) > 0 {
+		return r.lastDrawn
+	}
+	return p.Tiles[len(p.Tiles)-1]
+}
+
+// fewestSuit 手里张数最少的花色
+func fewestSuit(tiles []string) string {
+	missing := rules.SuitTiao
+	for _, suit := range rules.Suits {
+		if rules.CountSuit(tiles, suit) < rules.CountSuit(tiles, missing) {
+			missing = suit
+		}
+	}
+	return missing
+}
+
+// seconds 向上取整的秒数
+func seconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
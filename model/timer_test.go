@@ -0,0 +1,162 @@
+package model
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock 手动推进的时钟，计时器在 Advance 中按到期顺序触发
+type manualClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	clock   *manualClock
+	when    time.Time
+	f       func()
+	stopped bool
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{now: time.Unix(0, 0)}
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *manualClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	timer := &manualTimer{clock: c, when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	stopped := t.stopped
+	t.stopped = true
+	return !stopped
+}
+
+// Advance 推进时间并触发到期的计时器，包括触发过程中新创建的，调用时不能持有房间锁
+func (c *manualClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	until := c.now.Add(d)
+	c.mutex.Unlock()
+	for {
+		c.mutex.Lock()
+		sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].when.Before(c.timers[j].when) })
+		var next *manualTimer
+		for len(c.timers) > 0 && next == nil {
+			if c.timers[0].stopped {
+				c.timers = c.timers[1:]
+			} else if !c.timers[0].when.After(until) {
+				next = c.timers[0]
+				next.stopped = true
+				c.timers = c.timers[1:]
+				c.now = next.when
+			} else {
+				break
+			}
+		}
+		if next == nil {
+			c.now = until
+			c.mutex.Unlock()
+			return
+		}
+		c.mutex.Unlock()
+		next.f()
+	}
+}
+
+// timedRoom 使用手动时钟的测试房间
+func timedRoom(t *testing.T, players int, timing Timing) (*Room, *manualClock) {
+	room := newTestRoom(t, players)
+	clock := newManualClock()
+	room.Clock = clock
+	room.Timing = timing
+	return room, clock
+}
+
+func TestIdlePlayersAreTimedOutUntilTheHandEnds(t *testing.T) {
+	room, clock := timedRoom(t, 3, Timing{Dingque: 5 * time.Second, Turn: 5 * time.Second, Claim: 5 * time.Second})
+	var finished *Replay
+	room.OnHandFinished = func(r *Replay) error {
+		finished = r
+		return nil
+	}
+
+	room.Lock()
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+	room.Unlock()
+
+	// 没有人操作，定缺、出牌、响应都由超时完成
+	clock.Advance(time.Hour)
+	require.NotNil(t, finished, "超时处理没有让对局结束")
+	assert.Len(t, finished.Seats, 3)
+
+	room.Lock()
+	defer room.Unlock()
+	assert.Nil(t, room.timer, "本局结束后停止计时")
+	for _, p := range room.Players {
+		assert.NotEmpty(t, p.Missing)
+	}
+}
+
+func TestTurnTimerUsesTheTimeBank(t *testing.T) {
+	room, clock := timedRoom(t, 2, Timing{Dingque: time.Second, Turn: time.Second, Claim: time.Minute, TimeBank: 2 * time.Second})
+
+	room.Lock()
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+	for _, p := range room.Players {
+		require.NoError(t, room.HandleDingque(p.ID, fewestSuit(p.Tiles)))
+	}
+	current := room.Players[room.CurrentPlayerIndex]
+	assert.Equal(t, 3, room.PublicView().Seconds, "快照中的剩余时间包括时间银行，向上取整")
+	room.Unlock()
+
+	// 轮转消息带上基础时间和时间银行
+	turns := outboundFor(room.TakeOutbound(), room.Players[0].ID, MsgTurnChanged)
+	require.NotEmpty(t, turns)
+	turn := turns[len(turns)-1].Data.(TurnChangedData)
+	assert.Equal(t, current.ID, turn.PlayerID)
+	assert.Equal(t, 1, turn.Seconds)
+	assert.Equal(t, 2, turn.TimeBank)
+
+	// 超出基础时间的部分从时间银行扣除
+	clock.Advance(1500 * time.Millisecond)
+	room.Lock()
+	require.NoError(t, room.HandlePlayTile(current.ID, room.timeoutDiscard(current)))
+	bank := current.timeBank
+	room.StopTimer()
+	room.Unlock()
+	assert.Equal(t, 1500*time.Millisecond, bank)
+}
+
+func TestTimeoutDiscardPrefersTheMissingSuit(t *testing.T) {
+	room := newTestRoom(t, 2)
+	p := room.Players[0]
+	p.Missing = "w"
+
+	p.Tiles = []string{"1t", "2t", "5w", "9p"}
+	room.lastDrawn = "9p"
+	assert.Equal(t, "5w", room.timeoutDiscard(p))
+
+	p.Tiles = []string{"1t", "2t", "9p"}
+	assert.Equal(t, "9p", room.timeoutDiscard(p), "打出刚摸到的牌")
+
+	room.lastDrawn = ""
+	assert.Equal(t, "9p", room.timeoutDiscard(p), "碰牌之后打最后一张")
+}
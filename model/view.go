@@ -0,0 +1,126 @@
+package model
+
+import (
+	"encoding/json"
+	"goMahjong/rules"
+)
+
+// 发给客户端的对局状态都经过这里的视图生成，视图决定每个收件人能看到什么：
+// 牌桌视图是所有人都能看到的公开信息；座位视图在牌桌视图上加上自己的手牌、可执行的动作和还没公布的定缺；
+// 观战视图只有公开信息；本局结束后的亮牌视图公开所有人的手牌
+// Room 和 Player 本身不直接序列化，序列化时也只输出公开信息
+// 视图中的切片都是拷贝：消息进入发送队列后在房间锁之外编码，也可能留在消息记录里补发，不能和对局状态共享
+
+// PublicView 牌桌视图：所有人都能看到的对局状态，不包括任何人的手牌
+// 定缺在所有人定缺完成前不公开，暗杠的牌面只有本人能看到；
+// 等待碰杠胡时显示为即将摸牌，和没有人能碰杠胡时一样，不公开有人在等这张牌
+func (r *Room) PublicView() GameStateData {
+	seats := make([]SeatInfo, 0)
+	for _, p := range r.Players {
+		seat := SeatInfo{
+			ID:        p.ID,
+			Name:      p.Name,
+			Score:     p.Score,
+			Connected: p.Connected,
+			IsOwner:   r.IsOwner(p.ID),
+			IsBot:     p.Bot,
+			BotLevel:  p.BotLevel,
+			AutoPlay:  p.AutoPlay,
+			TimeBank:  seconds(p.timeBank),
+			TileCount: len(p.Tiles),
+			Discards:  copyTiles(p.Discards),
+			Melds:     publicMelds(p.Melds),
+		}
+		if r.Phase != PhaseDingque {
+			seat.Missing = p.Missing
+		}
+		seats = append(seats, seat)
+	}
+
+	phase, left := r.Phase, r.timeLeft()
+	if phase == PhaseClaim {
+		phase, left = PhaseDraw, 0
+	}
+	return GameStateData{
+		GameState:          r.GameState,
+		Players:            seats,
+		CurrentPlayerIndex: r.CurrentPlayerIndex,
+		CurrentPlayerID:    r.Players[r.CurrentPlayerIndex].ID,
+		DiscardedTiles:     copyTiles(r.DiscardedTiles),
+		RemainingTiles:     len(r.Tiles),
+		Phase:              phase,
+		DealerID:           r.Players[r.DealerIndex].ID,
+		Seconds:            seconds(left),
+	}
+}
+
+// SeatView 座位视图：某个玩家视角的完整对局状态，用于开局后加入和断线重连
+// 只包含该玩家自己的手牌，其他玩家只有公开信息；不在座位上的人得到观战视图
+// 只有还没做出选择的能碰杠胡的玩家看到响应阶段
+func (r *Room) SeatView(playerID string) GameSnapshotData {
+	snapshot := r.SpectatorView()
+	player := r.GetPlayer(playerID)
+	if player == nil {
+		return snapshot
+	}
+	snapshot.Tiles = copyTiles(player.Tiles)
+	snapshot.Options = r.playerOptions(player)
+	if len(r.pendingClaimOptions(playerID)) > 0 {
+		snapshot.Phase = PhaseClaim
+		snapshot.Seconds = seconds(r.timeLeft())
+	}
+	for i := range snapshot.Players {
+		if snapshot.Players[i].ID == playerID {
+			snapshot.Players[i].Missing = player.Missing
+			snapshot.Players[i].Melds = append(make([]rules.Meld, 0, len(player.Melds)), player.Melds...)
+		}
+	}
+	return snapshot
+}
+
+// SpectatorView 观战视图：牌桌视图加上最后打出的牌，没有手牌也没有可执行的动作
+func (r *Room) SpectatorView() GameSnapshotData {
+	return GameSnapshotData{
+		GameStateData:  r.PublicView(),
+		LastPlayedTile: r.LastPlayedTile,
+	}
+}
+
+// RevealView 亮牌视图：本局结束后公开所有人的手牌，对局进行中返回nil
+func (r *Room) RevealView() []RevealedHand {
+	if r.GameState == GameStatePlaying {
+		return nil
+	}
+	hands := make([]RevealedHand, 0, len(r.Players))
+	for _, p := range r.Players {
+		hands = append(hands, RevealedHand{PlayerID: p.ID, Tiles: copyTiles(p.Tiles)})
+	}
+	return hands
+}
+
+// publicMelds 其他人能看到的副露，暗杠隐去牌面
+func publicMelds(melds []rules.Meld) []rules.Meld {
+	public := make([]rules.Meld, len(melds))
+	for i, m := range melds {
+		if m.Kind == rules.MeldAnGang {
+			m.Tile = ""
+		}
+		public[i] = m
+	}
+	return public
+}
+
+// copyTiles 拷贝一组牌，空的也拷贝成空切片，序列化后是 [] 而不是 null
+func copyTiles(tiles []string) []string {
+	return append(make([]string, 0, len(tiles)), tiles...)
+}
+
+// MarshalJSON 房间只按房间信息序列化，避免把手牌和牌墙写进日志或接口
+func (r *Room) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.GetRoomInfo())
+}
+
+// MarshalJSON 玩家只按公开信息序列化
+func (p *Player) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.GetPublicInfo())
+}
@@ -0,0 +1,264 @@
+package model
+
+import (
+	"encoding/json"
+	"goMahjong/rules"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonKeys 序列化之后出现的所有字段名
+func jsonKeys(t *testing.T, v interface{}) map[string]bool {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	var decoded interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	keys := make(map[string]bool)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				keys[k] = true
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(decoded)
+	return keys
+}
+
+// checkPublic 牌桌视图中不能有收件人以外的人还没公开的信息
+func checkPublic(t *testing.T, state GameStateData, recipientID string) {
+	for _, seat := range state.Players {
+		if seat.ID == recipientID {
+			continue
+		}
+		if state.Phase == PhaseDingque {
+			assert.Empty(t, seat.Missing, "定缺完成前看到了 %s 的定缺", seat.Name)
+		}
+		for _, m := range seat.Melds {
+			if m.Kind == rules.MeldAnGang {
+				assert.Empty(t, m.Tile, "看到了 %s 暗杠的牌", seat.Name)
+			}
+		}
+	}
+}
+
+// checkMessage 发给 recipient 的消息不能泄露其他人的手牌、摸到的牌、暗杠和可执行的动作
+func checkMessage(t *testing.T, room *Room, recipient *Player, message Message) {
+	// 只有开局、快照和本局结束的消息可以带手牌
+	switch message.Type {
+	case MsgGameStarted, MsgGameSnapshot, MsgGameOver:
+	default:
+		assert.False(t, jsonKeys(t, message.Data)["tiles"], "%s 消息带有手牌", message.Type)
+	}
+
+	switch data := message.Data.(type) {
+	case GameStartedData:
+		checkPublic(t, data.GameStateData, recipient.ID)
+		assert.Equal(t, recipient.Tiles, data.Tiles)
+	case GameSnapshotData:
+		checkPublic(t, data.GameStateData, recipient.ID)
+		assert.Equal(t, recipient.Tiles, data.Tiles)
+	case NewTileData:
+		if data.PlayerID != recipient.ID {
+			assert.Empty(t, data.Tile, "看到了别人摸到的牌")
+		}
+	case MeldData:
+		if data.PlayerID != recipient.ID && data.Kind == rules.MeldAnGang {
+			assert.Empty(t, data.Tile, "看到了别人暗杠的牌")
+		}
+	case TurnChangedData:
+		if data.PlayerID != recipient.ID {
+			assert.Empty(t, data.Options, "看到了别人可以执行的动作")
+		}
+	case ClaimWindowData:
+		if data.From == recipient.ID {
+			assert.Empty(t, data.Options)
+		}
+	case GameOverData:
+		assert.Len(t, data.Hands, len(room.Players), "本局结束后公开所有人的手牌")
+	}
+}
+
+// 随机打完多局，每个人收到的每条消息和每个时刻的视图都不泄露隐藏的信息
+func TestViewsDoNotLeakHiddenInformation(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		room := newTestRoom(t, 2+int(seed%3))
+		require.NoError(t, room.StartGameBy(room.Owner.ID))
+
+		for steps := 0; ; steps++ {
+			require.Less(t, steps, 1000, "对局没有结束")
+			for _, out := range room.TakeOutbound() {
+				checkMessage(t, room, room.GetPlayer(out.PlayerID), out.Message)
+			}
+			if room.GameState != GameStatePlaying {
+				break
+			}
+
+			spectator := room.SpectatorView()
+			checkPublic(t, spectator.GameStateData, "")
+			assert.Empty(t, spectator.Tiles)
+			assert.Empty(t, spectator.Options)
+			assert.Nil(t, room.RevealView(), "对局进行中不亮牌")
+			for _, p := range room.Players {
+				checkMessage(t, room, p, Message{Type: MsgGameSnapshot, Data: room.SeatView(p.ID)})
+			}
+			step(t, room, rng)
+		}
+	}
+}
+
+// 视图和消息不和房间共享切片：发出之后对局继续进行，已经发出的内容也不会变
+// 连接在房间锁之外编码消息，共享切片会在编码时和对局的修改并发
+func TestViewsDoNotShareStateWithTheRoom(t *testing.T) {
+	type sent struct {
+		data    interface{}
+		encoded string
+	}
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	for seed := int64(1); seed <= 10; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		room := newTestRoom(t, 2+int(seed%3))
+		require.NoError(t, room.StartGameBy(room.Owner.ID))
+
+		history := make([]sent, 0)
+		keep := func(v interface{}) {
+			history = append(history, sent{data: v, encoded: encode(v)})
+		}
+		for steps := 0; room.GameState == GameStatePlaying; steps++ {
+			require.Less(t, steps, 1000, "对局没有结束")
+			for _, out := range room.TakeOutbound() {
+				keep(out.Message.Data)
+			}
+			for _, p := range room.Players {
+				keep(room.SeatView(p.ID))
+			}
+			keep(room.SpectatorView())
+			step(t, room, rng)
+		}
+		keep(room.RevealView())
+		// 下一局重新发牌，上一局的亮牌和所有消息都不受影响
+		require.NoError(t, room.StartGameBy(room.Owner.ID))
+		step(t, room, rng)
+
+		for _, h := range history {
+			require.Equal(t, h.encoded, encode(h.data), "种子 %d", seed)
+		}
+	}
+}
+
+// 序列化房间和玩家时只输出公开信息
+func TestRoomAndPlayerSerializeOnlyPublicInformation(t *testing.T) {
+	room := newTestRoom(t, 3)
+	require.NoError(t, room.StartGameBy(room.Owner.ID))
+
+	keys := jsonKeys(t, room)
+	for _, key := range []string{"tiles", "discardedTiles", "missing", "passwordHash"} {
+		assert.False(t, keys[key], "房间序列化出了 %s", key)
+	}
+	data, err := json.Marshal(room)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), room.Players[1].Name)
+
+	for _, p := range room.Players {
+		keys := jsonKeys(t, p)
+		assert.False(t, keys["tiles"])
+		assert.False(t, keys["missing"])
+	}
+}
+
+// 暗杠的牌面只有本人能看到，明杠和碰所有人都能看到
+func TestAnGangIsHiddenFromOthers(t *testing.T) {
+	room := newTestRoom(t, 2)
+	a, b := room.Players[0], room.Players[1]
+	require.NoError(t, room.StartGameWithDeal(scriptedDeal(t, room, []string{
+		"9t 9t 9t 9t 1w 1w 2w 2w 3w 3w 4w 4w 6w 6w",
+		"1t 2t 3t 4t 5t 6t 7t 8t 1p 2p 3p 4p 5p",
+	}, "7p")))
+	for _, p := range room.Players {
+		require.NoError(t, room.HandleDingque(p.ID, fewestSuit(p.Tiles)))
+	}
+	room.TakeOutbound()
+
+	require.NoError(t, room.HandlePlayerAction(a.ID, ActionGang, []string{"9t"}))
+	for _, out := range room.TakeOutbound() {
+		checkMessage(t, room, room.GetPlayer(out.PlayerID), out.Message)
+	}
+
+	assert.Equal(t, "9t", room.SeatView(a.ID).Players[0].Melds[0].Tile, "自己能看到暗杠的牌")
+	assert.Empty(t, room.SeatView(b.ID).Players[0].Melds[0].Tile)
+	assert.Empty(t, room.SpectatorView().Players[0].Melds[0].Tile)
+	assert.Equal(t, rules.MeldAnGang, room.PublicView().Players[0].Melds[0].Kind, "暗杠本身是公开的")
+}
+
+// received 收件人收到的消息类型和序号
+func received(out []Outbound, playerID string) []string {
+	messages := make([]string, 0)
+	for _, o := range out {
+		if o.PlayerID == playerID {
+			messages = append(messages, o.Message.Type+"@"+strconv.FormatUint(o.Message.Seq, 10))
+		}
+	}
+	return messages
+}
+
+// 有人能碰杠胡时只有他收到提示，其他人收到的消息和没有人能碰杠胡时完全一样
+func TestClaimPromptsReachOnlyEligibleSeats(t *testing.T) {
+	play := func(thirdHand string) (room *Room, spectator *Player, out []Outbound) {
+		room = newTestRoom(t, 3)
+		spectator = NewPlayer("观众")
+		require.NoError(t, room.AddSpectator(spectator))
+		require.NoError(t, room.StartGameWithDeal(scriptedDeal(t, room, []string{
+			"1t 2t 3t 5t 1w 2w 3w 4w 5w 6w 7w 8w 9w 9w",
+			"1p 2p 3p 4p 5p 6p 7p 8p 9p 1w 1w 2w 2w",
+			thirdHand,
+		}, "7p")))
+		a, b, c := room.Players[0], room.Players[1], room.Players[2]
+		require.NoError(t, room.HandleDingque(a.ID, rules.SuitTong))
+		require.NoError(t, room.HandleDingque(b.ID, rules.SuitTiao))
+		require.NoError(t, room.HandleDingque(c.ID, rules.SuitTong))
+		room.TakeOutbound()
+
+		require.NoError(t, room.HandlePlayTile(a.ID, "5t"))
+		if room.Phase == PhaseClaim {
+			out = room.TakeOutbound()
+			assert.Len(t, outboundFor(out, c.ID, MsgClaimWindow), 1)
+			assert.Equal(t, PhaseClaim, room.SeatView(c.ID).Phase)
+			assert.Equal(t, PhaseDraw, room.SeatView(b.ID).Phase, "其他人看不到响应阶段")
+			assert.Equal(t, PhaseDraw, room.SpectatorView().Phase)
+			replayed, ok := room.Replay(c.ID, room.Seq()-1)
+			require.True(t, ok)
+			assert.Equal(t, MsgClaimWindow, replayed[len(replayed)-1].Type, "补发时带上还没做出选择的提示")
+			require.NoError(t, room.HandlePlayerAction(c.ID, ActionPass, nil))
+		}
+		return room, spectator, append(out, room.TakeOutbound()...)
+	}
+
+	claimed, claimedSpectator, withClaim := play("5t 5t 6t 7t 8t 3w 3w 4w 4w 5w 6w 7w 8w")
+	require.Len(t, outboundFor(withClaim, claimed.Players[2].ID, MsgClaimWindow), 1, "第三个人可以碰")
+	assert.Empty(t, outboundFor(withClaim, claimed.Players[1].ID, MsgClaimWindow))
+	assert.Empty(t, outboundFor(withClaim, claimedSpectator.ID, MsgClaimWindow))
+
+	plain, plainSpectator, withoutClaim := play("4t 6t 6t 7t 8t 3w 3w 4w 4w 5w 6w 7w 8w")
+	assert.Empty(t, outboundFor(withoutClaim, plain.Players[2].ID, MsgClaimWindow), "没有人能碰杠胡")
+	assert.NotEmpty(t, received(withClaim, claimed.Players[1].ID))
+	assert.Equal(t, received(withoutClaim, plain.Players[1].ID), received(withClaim, claimed.Players[1].ID))
+	assert.Equal(t, received(withoutClaim, plainSpectator.ID), received(withClaim, claimedSpectator.ID))
+}
@@ -0,0 +1,5 @@
+// Package mahjongpb 是 proto/mahjong.proto 生成的Go类型，codec 包用它们编解码protobuf子协议
+// 修改 .proto 之后用 protoc 和 protoc-gen-go 重新生成，不要手动编辑 mahjong.pb.go
+package mahjongpb
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=module=goMahjong proto/mahjong.proto
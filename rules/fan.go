@@ -0,0 +1,87 @@
+package rules
+
+// 番型
+const (
+	PatternPingHu      = "平胡"
+	PatternAllTriplets = "对对胡"
+	PatternSevenPairs  = "七对"
+	PatternPureSuit    = "清一色"
+	PatternRoot        = "根"
+	PatternSelfDrawn   = "自摸"
+	PatternGangFlower  = "杠上开花"
+	PatternGangCannon  = "杠上炮"
+	PatternLastTile    = "海底"
+)
+
+// WinContext 胡牌时的情形
+type WinContext struct {
+	Hand      []string // 胡牌时的手牌，包括胡的那张
+	Melds     []Meld
+	SelfDrawn bool // 自摸
+	AfterGang bool // 自摸的是杠后补的牌，或者点炮的牌是杠后打出的
+	LastTile  bool // 胡的是牌墙最后一张
+}
+
+// Fan 计算番数，返回总番数和命中的番型
+// 四川麻将不能吃，副露只有碰和杠，所以手里全是刻子就算对对胡
+// 平胡0番；对对胡1番；七对2番；清一色2番；每个根（四张相同的牌）1番；
+// 自摸、杠上开花、杠上炮、海底各1番
+func Fan(ctx WinContext) (int, []string) {
+	fan := 0
+	patterns := make([]string, 0)
+	add := func(pattern string, n int) {
+		fan += n
+		patterns = append(patterns, pattern)
+	}
+
+	c := counts(ctx.Hand)
+	allTiles := append([]string(nil), ctx.Hand...)
+	for _, m := range ctx.Melds {
+		for i := 0; i < m.Size(); i++ {
+			allTiles = append(allTiles, m.Tile)
+		}
+	}
+
+	switch {
+	case len(ctx.Melds) == 0 && isSevenPairs(c, len(ctx.Hand)):
+		add(PatternSevenPairs, 2)
+	case isAllTriplets(c):
+		add(PatternAllTriplets, 1)
+	default:
+		add(PatternPingHu, 0)
+	}
+
+	suit := Suit(allTiles[0])
+	if CountSuit(allTiles, suit) == len(allTiles) {
+		add(PatternPureSuit, 2)
+	}
+
+	for _, n := range counts(allTiles) {
+		if n == 4 {
+			add(PatternRoot, 1)
+		}
+	}
+
+	if ctx.SelfDrawn {
+		add(PatternSelfDrawn, 1)
+	}
+	if ctx.AfterGang {
+		if ctx.SelfDrawn {
+			add(PatternGangFlower, 1)
+		} else {
+			add(PatternGangCannon, 1)
+		}
+	}
+	if ctx.LastTile {
+		add(PatternLastTile, 1)
+	}
+	return fan, patterns
+}
+
+// Points 按番数计算一家应付的分数：底分乘以2的番数次方，超过封顶番数按封顶计算
+func Points(base, fan, maxFan int) int {
+	if fan > maxFan {
+		fan = maxFan
+	}
+	return base << fan
+}
@@ -0,0 +1,105 @@
+package rules
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update 用 go test ./rules -update 重新生成 testdata 下的期望结果，生成后要人工核对差异再提交
+var update = flag.Bool("update", false, "重新生成 testdata 下的 golden 文件")
+
+// golden 比较输出和 testdata 下的 golden 文件
+func golden(t *testing.T, name string, got string) {
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(got), 0644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), got, "与 %s 不一致，确认改动正确后用 -update 重新生成", path)
+}
+
+// parseMelds 解析副露，格式为 种类:牌，如 peng:5t an_gang:9p
+func parseMelds(s string) []Meld {
+	melds := make([]Meld, 0)
+	for _, field := range strings.Fields(s) {
+		kind, tile, _ := strings.Cut(field, ":")
+		melds = append(melds, Meld{Kind: kind, Tile: tile})
+	}
+	return melds
+}
+
+// 胡牌判断和番型的固定结果，规则改动时能看到具体是哪些牌型的结果变了
+func TestWinGolden(t *testing.T) {
+	cases := []struct {
+		hand    string
+		melds   string
+		missing string
+		ctx     WinContext
+	}{
+		// 平胡
+		{hand: "1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p 5p", missing: "w"},
+		{hand: "1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p 5p", missing: "w", ctx: WinContext{SelfDrawn: true}},
+		{hand: "2t 3t 4t 5p 5p", melds: "peng:7t peng:1p peng:9p", missing: "w"},
+		// 对对胡
+		{hand: "1t 1t 1t 3t 3t 3t 5p 5p 5p 7p 7p 7p 9p 9p", missing: "w"},
+		{hand: "3t 3t 3t 9p 9p", melds: "peng:1t ming_gang:5p an_gang:7p", missing: "w"},
+		// 七对，龙七对
+		{hand: "1t 1t 2t 2t 3t 3t 4t 4t 5p 5p 6p 6p 7p 7p", missing: "w"},
+		{hand: "1t 1t 1t 1t 3t 3t 4t 4t 5p 5p 6p 6p 7p 7p", missing: "w"},
+		// 清一色
+		{hand: "1t 1t 1t 2t 3t 4t 5t 6t 7t 8t 8t 9t 9t 9t", missing: "w"},
+		{hand: "1t 1t 2t 2t 3t 3t 4t 4t 5t 5t 6t 6t 7t 7t", missing: "w"},
+		{hand: "2t 2t 2t 8t 8t", melds: "peng:1t bu_gang:5t an_gang:9t", missing: "p", ctx: WinContext{SelfDrawn: true, AfterGang: true}},
+		// 根
+		{hand: "1t 1t 1t 1t 2t 3t 5p 6p 7p 8p 8p 8p 9p 9p", missing: "w"},
+		{hand: "5t 6t 7t 9p 9p", melds: "peng:5t peng:2p peng:3p", missing: "w"},
+		// 杠上炮、海底
+		{hand: "1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p 5p", missing: "w", ctx: WinContext{AfterGang: true}},
+		{hand: "1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p 5p", missing: "w", ctx: WinContext{SelfDrawn: true, LastTile: true}},
+		// 不能胡：没有缺一门、张数不对、只差一张
+		{hand: "1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5w 5w", missing: "w"},
+		{hand: "1t 2t 3t 1p 2p 3p 1w 2w 3w 4t 5t 6t 9p 9p", missing: "w"},
+		{hand: "1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p", missing: "w"},
+		{hand: "1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p 6p", missing: "w"},
+		{hand: "1t 1t 2t 2t 3t 3t 4t 4t 5p 5p 6p 6p 7p 8p", missing: "w"},
+	}
+
+	var b strings.Builder
+	for _, c := range cases {
+		hand := strings.Fields(c.hand)
+		ctx := c.ctx
+		ctx.Hand = hand
+		ctx.Melds = parseMelds(c.melds)
+		fmt.Fprintf(&b, "%s | %s | 缺%s 自摸=%t 杠后=%t 海底=%t\n", c.hand, c.melds, c.missing, ctx.SelfDrawn, ctx.AfterGang, ctx.LastTile)
+		if !CanWin(hand, c.missing) {
+			fmt.Fprintf(&b, "  不能胡 向听=%d\n", Shanten(hand, c.missing))
+			continue
+		}
+		fan, patterns := Fan(ctx)
+		fmt.Fprintf(&b, "  胡 %d番 %s\n", fan, strings.Join(patterns, " "))
+	}
+	golden(t, "win", b.String())
+}
+
+// 底分、番数和封顶对应的分数表
+func TestPointsGolden(t *testing.T) {
+	var b strings.Builder
+	for _, maxFan := range []int{3, 4, 6} {
+		for _, base := range []int{1, 2, 5} {
+			fmt.Fprintf(&b, "封顶%d 底分%d:", maxFan, base)
+			for fan := 0; fan <= 7; fan++ {
+				fmt.Fprintf(&b, " %d", Points(base, fan, maxFan))
+			}
+			b.WriteString("\n")
+		}
+	}
+	golden(t, "points", b.String())
+}
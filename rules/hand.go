@@ -0,0 +1,231 @@
+package rules
+
+// 副露种类
+const (
+	MeldPeng     = "peng"      // 碰
+	MeldMingGang = "ming_gang" // 明杠，杠别人打出的牌
+	MeldAnGang   = "an_gang"   // 暗杠，手里四张
+	MeldBuGang   = "bu_gang"   // 补杠，碰过之后摸到第四张
+)
+
+// Meld 一组副露
+type Meld struct {
+	Kind string `json:"kind" pb:"1"`
+	Tile string `json:"tile" pb:"2"`
+	From string `json:"from,omitempty" pb:"3"` // 被碰、被杠的玩家ID，暗杠和补杠为空
+}
+
+// IsGang 是否是杠
+func (m Meld) IsGang() bool {
+	return m.Kind != MeldPeng
+}
+
+// Size 副露包含的牌数
+func (m Meld) Size() int {
+	if m.IsGang() {
+		return 4
+	}
+	return 3
+}
+
+// IsWinning 判断手牌是否已经胡牌，手牌张数应为 3n+2
+// 支持一般胡牌型（n组刻子或顺子加一对将）和七对
+func IsWinning(hand []string) bool {
+	if len(hand)%3 != 2 {
+		return false
+	}
+	c := counts(hand)
+	return isSevenPairs(c, len(hand)) || isStandard(c)
+}
+
+// CanWin 判断定缺后能否胡牌，手里还有缺门的牌不能胡
+func CanWin(hand []string, missing string) bool {
+	if missing != "" && CountSuit(hand, missing) > 0 {
+		return false
+	}
+	return IsWinning(hand)
+}
+
+// isSevenPairs 七对：14张全是对子，四张相同的算两对
+func isSevenPairs(c [TileKinds]int, size int) bool {
+	if size != 14 {
+		return false
+	}
+	for _, n := range c {
+		if n%2 != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isStandard 一般胡牌型：选一对将，剩下的都能拆成刻子或顺子
+func isStandard(c [TileKinds]int) bool {
+	for i := range c {
+		if c[i] < 2 {
+			continue
+		}
+		c[i] -= 2
+		ok := decompose(c)
+		c[i] += 2
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// decompose 判断能否全部拆成刻子或顺子
+func decompose(c [TileKinds]int) bool {
+	i := 0
+	for i < TileKinds && c[i] == 0 {
+		i++
+	}
+	if i == TileKinds {
+		return true
+	}
+
+	// 最小的一张要么组成刻子，要么作为顺子的开头
+	if c[i] >= 3 {
+		c[i] -= 3
+		if decompose(c) {
+			return true
+		}
+		c[i] += 3
+	}
+	if i%9 <= 6 && c[i+1] > 0 && c[i+2] > 0 {
+		c[i]--
+		c[i+1]--
+		c[i+2]--
+		return decompose(c)
+	}
+	return false
+}
+
+// isAllTriplets 对对胡：一对将加上全部是刻子
+func isAllTriplets(c [TileKinds]int) bool {
+	pairs := 0
+	for _, n := range c {
+		switch n {
+		case 0, 3:
+		case 2:
+			pairs++
+		default:
+			return false
+		}
+	}
+	return pairs == 1
+}
+
+// Shanten 向听数：还要换几张牌才能听牌，0 表示听牌，-1 表示已经胡牌
+// hand 是手里的牌（不含碰杠），张数为 3n+1 或 3n+2；缺门的牌不能留，按孤张计算
+// 取一般牌型和七对中较小的一个
+func Shanten(hand []string, missing string) int {
+	var c [TileKinds]int
+	for _, t := range hand {
+		if Suit(t) != missing {
+			c[Index(t)]++
+		}
+	}
+	best := standardShanten(c, len(hand)/3)
+	if len(hand) >= 13 {
+		if s := sevenPairsShanten(c); s < best {
+			best = s
+		}
+	}
+	return best
+}
+
+// standardShanten 一般牌型的向听数：sets 是还需要的面子数，
+// 向听数 = 2*sets - 2*面子 - 搭子 - 将，面子和搭子合计不超过 sets
+func standardShanten(c [TileKinds]int, sets int) int {
+	left := 0
+	for _, n := range c {
+		left += n
+	}
+
+	best := 2 * sets
+	var search func(i, left, melds, partials int, pair bool)
+	search = func(i, left, melds, partials int, pair bool) {
+		s := 2*sets - 2*melds - partials
+		if melds+partials > sets {
+			s += melds + partials - sets
+		}
+		if pair {
+			s--
+		}
+		// 剩下的牌每3张最多减少2向听，再加一个将，仍然不会更好时剪枝
+		if s-left*2/3-1 >= best {
+			return
+		}
+		for i < TileKinds && c[i] == 0 {
+			i++
+		}
+		if i == TileKinds {
+			best = s
+			return
+		}
+
+		// 刻子、顺子
+		if c[i] >= 3 {
+			c[i] -= 3
+			search(i, left-3, melds+1, partials, pair)
+			c[i] += 3
+		}
+		if i%9 <= 6 && c[i+1] > 0 && c[i+2] > 0 {
+			c[i]--
+			c[i+1]--
+			c[i+2]--
+			search(i, left-3, melds+1, partials, pair)
+			c[i]++
+			c[i+1]++
+			c[i+2]++
+		}
+		// 将
+		if !pair && c[i] >= 2 {
+			c[i] -= 2
+			search(i, left-2, melds, partials, true)
+			c[i] += 2
+		}
+		// 搭子：对子、两面或边张、嵌张，面子和搭子已经够数时不再找搭子
+		if melds+partials < sets {
+			if c[i] >= 2 {
+				c[i] -= 2
+				search(i, left-2, melds, partials+1, pair)
+				c[i] += 2
+			}
+			if i%9 <= 7 && c[i+1] > 0 {
+				c[i]--
+				c[i+1]--
+				search(i, left-2, melds, partials+1, pair)
+				c[i]++
+				c[i+1]++
+			}
+			if i%9 <= 6 && c[i+2] > 0 {
+				c[i]--
+				c[i+2]--
+				search(i, left-2, melds, partials+1, pair)
+				c[i]++
+				c[i+2]++
+			}
+		}
+		// 孤张
+		c[i]--
+		search(i, left-1, melds, partials, pair)
+		c[i]++
+	}
+	search(0, left, 0, 0, false)
+	return best
+}
+
+// sevenPairsShanten 七对的向听数，四张相同的算两对
+func sevenPairsShanten(c [TileKinds]int) int {
+	pairs := 0
+	for _, n := range c {
+		pairs += n / 2
+	}
+	if pairs > 7 {
+		pairs = 7
+	}
+	return 6 - pairs
+}
@@ -0,0 +1,51 @@
+package rules
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShanten(t *testing.T) {
+	cases := []struct {
+		hand    string
+		missing string
+		want    int
+	}{
+		{"1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p 5p", "w", -1},
+		{"1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5p", "w", 0},
+		{"1t 1t 2t 2t 3t 3t 4t 4t 5p 5p 6p 6p 7p 7p", "w", -1},
+		{"1t 1t 2t 2t 3t 3t 4t 4t 5p 5p 6p 6p 9p", "w", 0},
+		{"1t 2t 3t 4t 5t 6t 7t 8t 9t 1p 2p 3p 5w", "w", 0},
+		{"1t 4t 7t 1p 4p 7p 2w 5w 8w 3t 6t 9t 3p", "", 4},
+		{"1t 4t 7t 1p 4p 7p 2w 5w 8w 3t 6t 9t 3p", "t", 6},
+		{"1t 2t 3t 5p 5p", "w", -1},
+		{"1t 2t 3t 5p", "w", 0},
+		{"1t 2t 5p 9p", "w", 1},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, Shanten(strings.Fields(c.hand), c.missing), c.hand)
+	}
+}
+
+// 向听数为-1 与能胡牌一致
+func TestShantenMatchesCanWin(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		wall := NewWall()
+		rng.Shuffle(len(wall), func(a, b int) { wall[a], wall[b] = wall[b], wall[a] })
+		hand := wall[:14]
+		if i%2 == 0 {
+			// 随机手牌几乎不会胡，混入同一花色的牌提高胡牌的比例
+			hand = make([]string, 0, 14)
+			for _, t := range wall {
+				if Suit(t) != SuitWan && len(hand) < 14 {
+					hand = append(hand, t)
+				}
+			}
+		}
+		assert.Equal(t, CanWin(hand, SuitWan), Shanten(hand, SuitWan) == -1, hand)
+	}
+}
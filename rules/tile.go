@@ -0,0 +1,109 @@
+package rules
+
+import "sort"
+
+// 花色，牌的编码是点数加花色，如 "1t" 表示一条
+const (
+	SuitTiao = "t" // 条
+	SuitTong = "p" // 筒
+	SuitWan  = "w" // 万
+)
+
+// Suits 四川麻将只有条、筒、万三种花色
+var Suits = []string{SuitTiao, SuitTong, SuitWan}
+
+// TileKinds 牌的种类数，每种4张
+const TileKinds = 27
+
+// WallSize 整副牌的张数
+const WallSize = TileKinds * 4
+
+// Suit 返回牌的花色
+func Suit(tile string) string {
+	return tile[1:]
+}
+
+// Rank 返回牌的点数
+func Rank(tile string) int {
+	return int(tile[0] - '0')
+}
+
+// Index 返回牌在 0-26 中的序号，按条、筒、万排列
+func Index(tile string) int {
+	for i, suit := range Suits {
+		if Suit(tile) == suit {
+			return i*9 + Rank(tile) - 1
+		}
+	}
+	return -1
+}
+
+// TileAt 是 Index 的逆运算
+func TileAt(index int) string {
+	return string(rune('1'+index%9)) + Suits[index/9]
+}
+
+// NewWall 返回一副按顺序排列的牌，每种4张共108张
+func NewWall() []string {
+	wall := make([]string, 0, WallSize)
+	for i := 0; i < TileKinds; i++ {
+		for j := 0; j < 4; j++ {
+			wall = append(wall, TileAt(i))
+		}
+	}
+	return wall
+}
+
+// SortTiles 按花色和点数排序
+func SortTiles(tiles []string) {
+	sort.Slice(tiles, func(i, j int) bool {
+		return Index(tiles[i]) < Index(tiles[j])
+	})
+}
+
+// Count 统计某张牌的数量
+func Count(tiles []string, tile string) int {
+	n := 0
+	for _, t := range tiles {
+		if t == tile {
+			n++
+		}
+	}
+	return n
+}
+
+// CountSuit 统计某个花色的牌数
+func CountSuit(tiles []string, suit string) int {
+	n := 0
+	for _, t := range tiles {
+		if Suit(t) == suit {
+			n++
+		}
+	}
+	return n
+}
+
+// Remove 从牌中去掉n张指定的牌，返回新的切片，数量不够时返回false
+func Remove(tiles []string, tile string, n int) ([]string, bool) {
+	if Count(tiles, tile) < n {
+		return tiles, false
+	}
+	result := make([]string, 0, len(tiles)-n)
+	for _, t := range tiles {
+		if t == tile && n > 0 {
+			n--
+			continue
+		}
+		result = append(result, t)
+	}
+	return result, true
+}
+
+// counts 把牌转换成每种牌的数量
+func counts(tiles []string) [TileKinds]int {
+	var c [TileKinds]int
+	for _, t := range tiles {
+		c[Index(t)]++
+	}
+	return c
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"goMahjong/model"
+	"goMahjong/transport"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// systemClock 使用系统时间的时钟，服务端的房间都用它计时
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) model.Timer {
+	return time.AfterFunc(d, f)
+}
+
+// roomTiming 从配置读取房间的时间设置，创建或恢复房间时读取一次
+func roomTiming() model.Timing {
+	return model.Timing{
+		Dingque:    viper.GetDuration("game.dingqueTimeout"),
+		Turn:       viper.GetDuration("game.turnTimeout"),
+		Claim:      viper.GetDuration("game.claimTimeout"),
+		TimeBank:   viper.GetDuration("game.timeBank"),
+		CoachDelay: viper.GetDuration("game.coachDelay"),
+	}
+}
+
+// runRoom 给房间接上客户端登记表和系统时钟
+func runRoom(room *model.Room) {
+	room.Transport = transport.NewClients()
+	room.Clock = systemClock{}
+	room.Timing = roomTiming()
+}
@@ -1,36 +1,336 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"goMahjong/config"
 	"goMahjong/model"
+	"goMahjong/store"
+	"strconv"
 	"sync"
+	"time"
+
+	"github.com/spf13/viper"
 )
 
 // GameManager 游戏管理器，管理所有房间
+// 进行中的房间带着连接和锁，保存在内存中；房间、玩家、对局结果和回放的记录通过 store 持久化
 type GameManager struct {
-	rooms map[string]*model.Room
-	mutex sync.RWMutex
+	rooms    map[string]*model.Room
+	mutex    sync.RWMutex
+	sessions *SessionManager
+	store    store.Store
+
+	savedSeq   map[string]uint64 // 房间ID -> 最近一次保存时的消息序号，序号没变的房间不需要再做检查点
+	savedMutex sync.Mutex
+
+	drainDeadline time.Time // 停服时断开连接的时间，为零表示正常服务，由 mutex 保护
+
+	creators map[string]string // 房间ID -> 创建者的IP，用于限制每个IP的房间数，由 mutex 保护
+
+	watch      map[string]*roomWatch // 房间ID -> 清理协程的观察记录
+	watchMutex sync.Mutex
+
+	// OnRoomEvent 房间创建、恢复和关闭时调用，为空时不通知；调用时可能持有房间锁，不能阻塞
+	OnRoomEvent func(RoomEvent)
 }
 
-// NewGameManager 创建一个新的游戏管理器
-func NewGameManager() *GameManager {
+// ErrDraining 服务器正在停止，不再创建房间
+var ErrDraining = errors.New("server is shutting down")
 
+// ErrTooManyRooms 房间总数达到了 room.maxRooms
+var ErrTooManyRooms = errors.New("too many rooms")
+
+// ErrTooManyRoomsForIP 同一个IP创建的房间数达到了 room.maxRoomsPerIP
+var ErrTooManyRoomsForIP = errors.New("too many rooms for this address")
+
+// metaSessionSecret 存储中自动生成的会话密钥
+const metaSessionSecret = "sessionSecret"
+
+// NewGameManager 创建一个新的游戏管理器
+func NewGameManager(st store.Store) *GameManager {
 	return &GameManager{
 		// make不填先填长度，默认长度为0
-		rooms: make(map[string]*model.Room),
+		rooms:    make(map[string]*model.Room),
+		sessions: NewSessionManager(sessionSecret(st), viper.GetDuration("security.sessionTTL")),
+		store:    st,
+		savedSeq: make(map[string]uint64),
+		creators: make(map[string]string),
+		watch:    make(map[string]*roomWatch),
 	}
 }
 
-// CreateRoom 创建一个新房间
-func (gm *GameManager) CreateRoom(password string) (*model.Room, error) {
+// sessionSecret 返回会话密钥：优先使用配置，没有配置时使用存储中保存的随机密钥，
+// 这样恢复房间后玩家手里的令牌仍然有效
+func sessionSecret(st store.Store) string {
+	logger := config.GetZapLogger()
+	if secret := viper.GetString("security.sessionSecret"); secret != "" {
+		return secret
+	}
+
+	secret, err := st.GetMeta(metaSessionSecret)
+	if err == nil {
+		return secret
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	secret = base64.RawURLEncoding.EncodeToString(key)
+	if err := st.SaveMeta(metaSessionSecret, secret); err != nil {
+		logger.Warn("保存会话密钥失败，重启后玩家需要重新加入房间: " + err.Error())
+	} else {
+		logger.Info("未配置 security.sessionSecret，使用存储中保存的随机密钥")
+	}
+	return secret
+}
+
+// IssueSession 为房间中的玩家签发会话令牌
+func (gm *GameManager) IssueSession(roomID, playerID string) string {
+	return gm.sessions.Issue(roomID, playerID)
+}
+
+// VerifySession 校验会话令牌，返回令牌对应的房间ID和玩家ID
+func (gm *GameManager) VerifySession(token string) (string, string, error) {
+	return gm.sessions.Verify(token)
+}
+
+// CreateRoom 创建一个新房间，加入房主后需要调用 SaveRoom 保存
+// creatorIP 是创建者的IP，为空时不计入每个IP的房间数
+func (gm *GameManager) CreateRoom(password, creatorIP string) (*model.Room, error) {
 	gm.mutex.Lock()
 	defer gm.mutex.Unlock()
 
-	room := model.NewRoom(password)
+	if !gm.drainDeadline.IsZero() {
+		return nil, ErrDraining
+	}
+	if max := viper.GetInt("room.maxRooms"); max > 0 && len(gm.rooms) >= max {
+		return nil, ErrTooManyRooms
+	}
+	if max := viper.GetInt("room.maxRoomsPerIP"); max > 0 && creatorIP != "" && gm.roomsCreatedBy(creatorIP) >= max {
+		return nil, ErrTooManyRoomsForIP
+	}
+	room, err := model.NewRoom(password)
+	if err != nil {
+		return nil, err
+	}
+	runRoom(room)
+	room.OnHandFinished = func(replay *model.Replay) error {
+		return gm.handFinished(room, replay)
+	}
 	gm.rooms[room.ID] = room
+	if creatorIP != "" {
+		gm.creators[room.ID] = creatorIP
+	}
+	config.GetZapLogger().Info("已创建房间 " + room.ID)
+	gm.emit(RoomEvent{Type: RoomCreated, RoomID: room.ID})
+
+	return room, nil
+}
+
+// roomsCreatedBy 某个IP创建的、还没有关闭的房间数，调用方需持有 mutex
+func (gm *GameManager) roomsCreatedBy(ip string) int {
+	count := 0
+	for _, creator := range gm.creators {
+		if creator == ip {
+			count++
+		}
+	}
+	return count
+}
+
+// SaveRoom 把房间和其中的玩家写入存储，已经离开的玩家一并删除
+// 房间变化（加入、离开、换房主、开局、结算）后调用，调用方需持有房间锁
+func (gm *GameManager) SaveRoom(room *model.Room) {
+	logger := config.GetZapLogger()
+
+	record := store.RoomRecord{
+		ID:           room.ID,
+		PasswordHash: room.PasswordHash,
+		PlayerIDs:    make([]string, 0, len(room.Players)),
+		GameState:    room.GameState,
+		Seq:          room.Seq(),
+		Hand:         room.HandEvents(),
+		UpdatedAt:    time.Now(),
+	}
+	gm.mutex.RLock()
+	record.CreatorIP = gm.creators[room.ID]
+	gm.mutex.RUnlock()
+	if room.Owner != nil {
+		record.OwnerID = room.Owner.ID
+	}
+	for _, p := range room.Players {
+		record.PlayerIDs = append(record.PlayerIDs, p.ID)
+		player := store.PlayerRecord{ID: p.ID, RoomID: room.ID, Name: p.Name, Score: p.Score, Bot: p.Bot, BotLevel: p.BotLevel}
+		if err := gm.store.SavePlayer(player); err != nil {
+			logger.Error("保存玩家 " + p.ID + " 失败: " + err.Error())
+		}
+	}
+
+	if old, err := gm.store.GetRoom(room.ID); err == nil {
+		for _, id := range old.PlayerIDs {
+			if room.GetPlayer(id) == nil {
+				gm.deletePlayer(id)
+			}
+		}
+	}
+	if err := gm.store.SaveRoom(record); err != nil {
+		logger.Error("保存房间 " + room.ID + " 失败: " + err.Error())
+		return
+	}
+
+	gm.savedMutex.Lock()
+	gm.savedSeq[room.ID] = record.Seq
+	gm.savedMutex.Unlock()
+}
+
+// Checkpoint 保存所有自上次保存以来有变化的房间
+func (gm *GameManager) Checkpoint() {
+	for _, room := range gm.GetAllRooms() {
+		room.Lock()
+		gm.savedMutex.Lock()
+		saved, ok := gm.savedSeq[room.ID]
+		gm.savedMutex.Unlock()
+		if !ok || saved != room.Seq() {
+			gm.SaveRoom(room)
+		}
+		room.Unlock()
+	}
+}
+
+// StartCheckpoints 定期保存房间，直到ctx结束
+func (gm *GameManager) StartCheckpoints(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				gm.Checkpoint()
+			}
+		}
+	}()
+}
 
+// RestoreRooms 启动时从存储中恢复房间，返回恢复出的房间
+// 恢复出的玩家都处于离线状态，调用方负责为他们保留座位等待重连
+func (gm *GameManager) RestoreRooms() []*model.Room {
+	logger := config.GetZapLogger()
+
+	records, err := gm.store.ListRooms()
+	if err != nil {
+		logger.Error("读取房间记录失败: " + err.Error())
+		return nil
+	}
+
+	restored := make([]*model.Room, 0, len(records))
+	for _, record := range records {
+		room, err := gm.restoreRoom(record)
+		if err != nil {
+			logger.Error("恢复房间 " + record.ID + " 失败: " + err.Error())
+			continue
+		}
+
+		gm.mutex.Lock()
+		gm.rooms[room.ID] = room
+		if record.CreatorIP != "" {
+			gm.creators[room.ID] = record.CreatorIP
+		}
+		gm.mutex.Unlock()
+		gm.savedMutex.Lock()
+		gm.savedSeq[room.ID] = room.Seq()
+		gm.savedMutex.Unlock()
+		restored = append(restored, room)
+		logger.Info("已恢复房间 " + room.ID + "，玩家 " + strconv.Itoa(len(room.Players)) + " 人")
+		gm.emit(RoomEvent{Type: RoomRestored, RoomID: room.ID})
+	}
+	return restored
+}
+
+// restoreRoom 重放房间最近一局的事件，再补上开局后才加入或还没开过局的玩家
+func (gm *GameManager) restoreRoom(record store.RoomRecord) (*model.Room, error) {
+	room, err := model.RestoreRoom(record.ID, record.PasswordHash, record.Hand, record.Seq)
+	if err != nil {
+		return nil, err
+	}
+	runRoom(room)
+
+	for _, id := range record.PlayerIDs {
+		saved, err := gm.store.GetPlayer(id)
+		if err != nil {
+			return nil, err
+		}
+		if player := room.GetPlayer(id); player != nil {
+			player.Bot, player.BotLevel = saved.Bot, saved.BotLevel
+			continue
+		}
+		player := model.NewPlayer(saved.Name)
+		player.ID = saved.ID
+		player.Score = saved.Score
+		player.Bot, player.BotLevel = saved.Bot, saved.BotLevel
+		room.AddPlayer(player)
+	}
+	// 开局后离开了房间的玩家不再恢复
+	for _, p := range append([]*model.Player(nil), room.Players...) {
+		if !contains(record.PlayerIDs, p.ID) {
+			room.RemovePlayer(p.ID)
+		}
+	}
+	if len(room.Humans()) == 0 {
+		return nil, errors.New("房间中没有真人玩家")
+	}
+
+	owner := room.GetPlayer(record.OwnerID)
+	if owner == nil {
+		owner = room.Humans()[0]
+	}
+	room.SetOwner(owner)
+	room.OnHandFinished = func(replay *model.Replay) error {
+		return gm.handFinished(room, replay)
+	}
 	return room, nil
 }
 
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// handFinished 一局结束后保存回放、对局结果和玩家的新分数，调用时持有房间锁
+func (gm *GameManager) handFinished(room *model.Room, replay *model.Replay) error {
+	if err := gm.store.SaveReplay(replay); err != nil {
+		return err
+	}
+	if err := gm.store.SaveResult(store.NewMatchResult(replay)); err != nil {
+		return err
+	}
+	gm.SaveRoom(room)
+	return nil
+}
+
+// SaveReplay 保存回放（导入的牌谱）
+func (gm *GameManager) SaveReplay(replay *model.Replay) error {
+	return gm.store.SaveReplay(replay)
+}
+
+// GetReplay 获取指定ID的回放，不存在时返回 store.ErrNotFound
+func (gm *GameManager) GetReplay(replayID string) (*model.Replay, error) {
+	return gm.store.GetReplay(replayID)
+}
+
+// ListResults 获取房间的历史对局结果
+func (gm *GameManager) ListResults(roomID string) ([]store.MatchResult, error) {
+	return gm.store.ListResults(roomID)
+}
+
 // GetRoom 获取指定ID的房间
 func (gm *GameManager) GetRoom(roomID string) *model.Room {
 	gm.mutex.RLock()
@@ -39,12 +339,67 @@ func (gm *GameManager) GetRoom(roomID string) *model.Room {
 	return gm.rooms[roomID]
 }
 
-// RemoveRoom 移除指定ID的房间
+// RemoveRoom 真人玩家都离开后移除指定ID的房间
 func (gm *GameManager) RemoveRoom(roomID string) {
+	gm.removeRoom(roomID)
+	gm.emit(RoomEvent{Type: RoomClosed, RoomID: roomID, Reason: CloseReasonEmpty})
+}
+
+// removeRoom 从内存和存储中删除房间
+func (gm *GameManager) removeRoom(roomID string) {
 	gm.mutex.Lock()
 	defer gm.mutex.Unlock()
 
 	delete(gm.rooms, roomID)
+	delete(gm.creators, roomID)
+	gm.watchMutex.Lock()
+	delete(gm.watch, roomID)
+	gm.watchMutex.Unlock()
+	gm.savedMutex.Lock()
+	delete(gm.savedSeq, roomID)
+	gm.savedMutex.Unlock()
+
+	if record, err := gm.store.GetRoom(roomID); err == nil {
+		for _, id := range record.PlayerIDs {
+			gm.deletePlayer(id)
+		}
+	}
+	if err := gm.store.DeleteRoom(roomID); err != nil {
+		config.GetZapLogger().Error("删除房间 " + roomID + " 失败: " + err.Error())
+	}
+}
+
+func (gm *GameManager) deletePlayer(playerID string) {
+	if err := gm.store.DeletePlayer(playerID); err != nil {
+		config.GetZapLogger().Error("删除玩家 " + playerID + " 失败: " + err.Error())
+	}
+}
+
+// StartDraining 进入停服状态：不再创建房间，deadline 时断开所有连接
+func (gm *GameManager) StartDraining(deadline time.Time) {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+
+	gm.drainDeadline = deadline
+}
+
+// DrainDeadline 返回停服时断开连接的时间，没有在停服时第二个返回值为false
+func (gm *GameManager) DrainDeadline() (time.Time, bool) {
+	gm.mutex.RLock()
+	defer gm.mutex.RUnlock()
+
+	return gm.drainDeadline, !gm.drainDeadline.IsZero()
+}
+
+// Draining 服务器是否正在停止
+func (gm *GameManager) Draining() bool {
+	_, draining := gm.DrainDeadline()
+	return draining
+}
+
+// Close 关闭存储，调用前应先做最后一次检查点
+func (gm *GameManager) Close() error {
+	return gm.store.Close()
 }
 
 // GetAllRooms 获取所有房间
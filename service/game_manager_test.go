@@ -0,0 +1,111 @@
+package service
+
+import (
+	"goMahjong/model"
+	"goMahjong/store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameManagerPersistsRooms(t *testing.T) {
+	st := store.NewMemory()
+	gm := NewGameManager(st)
+
+	room, err := gm.CreateRoom("secret", "")
+	require.NoError(t, err)
+	owner, guest := model.NewPlayer("东"), model.NewPlayer("南")
+	room.AddPlayer(owner)
+	room.AddPlayer(guest)
+	room.SetOwner(owner)
+	gm.SaveRoom(room)
+
+	record, err := st.GetRoom(room.ID)
+	require.NoError(t, err)
+	assert.Equal(t, owner.ID, record.OwnerID)
+	assert.Equal(t, []string{owner.ID, guest.ID}, record.PlayerIDs)
+	assert.NotEmpty(t, record.PasswordHash)
+	player, err := st.GetPlayer(guest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, room.ID, player.RoomID)
+
+	// 离开的玩家从存储中删除
+	room.RemovePlayer(guest.ID)
+	gm.SaveRoom(room)
+	_, err = st.GetPlayer(guest.ID)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	// 一局结束时保存回放和对局结果
+	owner.Score = 4
+	replay := &model.Replay{ID: "r1", RoomID: room.ID, FinalScores: map[string]int{owner.ID: 4}, CreatedAt: time.Now()}
+	require.NoError(t, room.OnHandFinished(replay))
+	results, err := gm.ListResults(room.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "r1", results[0].ReplayID)
+	_, err = gm.GetReplay("r1")
+	assert.NoError(t, err)
+	player, err = st.GetPlayer(owner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, player.Score)
+
+	// 删除房间时删除房间和玩家记录，回放保留
+	gm.RemoveRoom(room.ID)
+	_, err = st.GetRoom(room.ID)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	_, err = st.GetPlayer(owner.ID)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	_, err = gm.GetReplay("r1")
+	assert.NoError(t, err)
+}
+
+func TestRestoreRoomsFromCheckpoint(t *testing.T) {
+	st := store.NewMemory()
+	before := NewGameManager(st)
+
+	room, err := before.CreateRoom("", "")
+	require.NoError(t, err)
+	owner, guest := model.NewPlayer("东"), model.NewPlayer("南")
+	room.AddPlayer(owner)
+	room.AddPlayer(guest)
+	room.SetOwner(owner)
+	require.NoError(t, room.StartGameBy(owner.ID))
+	require.NoError(t, room.HandleDingque(owner.ID, "t"))
+	require.NoError(t, room.HandleDingque(guest.ID, "p"))
+	before.Checkpoint()
+	token := before.IssueSession(room.ID, guest.ID)
+
+	// 另一个管理器从同一个存储恢复，相当于重启
+	after := NewGameManager(st)
+	restored := after.RestoreRooms()
+	require.Len(t, restored, 1)
+	got := restored[0]
+	assert.Same(t, got, after.GetRoom(room.ID))
+	assert.Equal(t, room.Phase, got.Phase)
+	assert.Equal(t, room.CurrentPlayerIndex, got.CurrentPlayerIndex)
+	assert.Equal(t, room.Tiles, got.Tiles)
+	assert.Equal(t, owner.ID, got.Owner.ID)
+	for i, p := range room.Players {
+		assert.Equal(t, p.ID, got.Players[i].ID)
+		assert.Equal(t, p.Name, got.Players[i].Name)
+		assert.Equal(t, p.Tiles, got.Players[i].Tiles)
+		assert.Equal(t, p.Missing, got.Players[i].Missing)
+	}
+	assert.Greater(t, got.Seq(), room.Seq(), "恢复后的序号要跳过重启前发出的消息")
+
+	// 重启前签发的令牌仍然有效
+	roomID, playerID, err := after.VerifySession(token)
+	require.NoError(t, err)
+	assert.Equal(t, room.ID, roomID)
+	assert.Equal(t, guest.ID, playerID)
+
+	// 没有变化的房间不再重复保存
+	record, err := st.GetRoom(room.ID)
+	require.NoError(t, err)
+	after.Checkpoint()
+	again, err := st.GetRoom(room.ID)
+	require.NoError(t, err)
+	assert.Equal(t, record.UpdatedAt, again.UpdatedAt)
+}
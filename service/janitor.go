@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"goMahjong/config"
+	"goMahjong/model"
+	"goMahjong/transport"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+)
+
+// 房间的生命周期：创建或恢复之后，所有人离开时由 RemoveRoom 删除；
+// 没有人离开却也没有人在线的房间（创建后从没连上、所有人都断线了）和打完之后一直没有再开局的房间由清理协程关闭
+
+// RoomEventType 房间生命周期事件的类型
+type RoomEventType string
+
+const (
+	RoomCreated  RoomEventType = "created"  // 创建了房间
+	RoomRestored RoomEventType = "restored" // 启动时恢复了房间
+	RoomClosed   RoomEventType = "closed"   // 房间被删除，Reason 说明原因
+)
+
+// 房间关闭的原因
+const (
+	CloseReasonEmpty    = "empty"    // 真人玩家都离开了
+	CloseReasonIdle     = "idle"     // 太久没有人在线
+	CloseReasonFinished = "finished" // 打完之后太久没有再开局
+)
+
+// RoomEvent 房间生命周期事件
+type RoomEvent struct {
+	Type   RoomEventType
+	RoomID string
+	Reason string
+	Time   time.Time
+}
+
+// roomWatch 清理协程对一个房间的观察记录
+type roomWatch struct {
+	seq       uint64    // 上次检查时房间的消息序号
+	changedAt time.Time // 房间最后一次有新消息的时间（以检查时间计）
+	idleSince time.Time // 开始没有人在线的时间，有人在线时为零
+}
+
+// Sweep 检查所有房间，关闭太久没有人在线和打完之后太久没有再开局的房间，返回关闭的房间数
+func (gm *GameManager) Sweep(now time.Time) int {
+	idleTimeout := viper.GetDuration("room.idleTimeout")
+	finishedTimeout := viper.GetDuration("room.finishedTimeout")
+
+	closed := 0
+	for _, room := range gm.GetAllRooms() {
+		room.Lock()
+		// 取出房间列表之后房间可能已经被删除
+		if gm.GetRoom(room.ID) != room {
+			room.Unlock()
+			continue
+		}
+		gm.watchMutex.Lock()
+		w, ok := gm.watch[room.ID]
+		if !ok {
+			w = &roomWatch{seq: room.Seq(), changedAt: now}
+			gm.watch[room.ID] = w
+		}
+		if room.Seq() != w.seq {
+			w.seq, w.changedAt = room.Seq(), now
+		}
+		if hasConnected(room) {
+			w.idleSince = time.Time{}
+		} else if w.idleSince.IsZero() {
+			w.idleSince = now
+		}
+
+		reason := ""
+		switch {
+		case idleTimeout > 0 && !w.idleSince.IsZero() && now.Sub(w.idleSince) >= idleTimeout:
+			reason = CloseReasonIdle
+		case finishedTimeout > 0 && room.GameState == model.GameStateFinished && now.Sub(w.changedAt) >= finishedTimeout:
+			reason = CloseReasonFinished
+		}
+		gm.watchMutex.Unlock()
+
+		if reason != "" {
+			gm.CloseRoom(room, reason)
+			closed++
+		}
+		room.Unlock()
+	}
+	return closed
+}
+
+// StartJanitor 定期清理房间，直到ctx结束
+func (gm *GameManager) StartJanitor(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				gm.Sweep(now)
+			}
+		}
+	}()
+}
+
+// CloseRoom 通知房间里的人房间已关闭，断开所有连接、停止机器人和计时器后删除房间，调用方需持有房间锁
+func (gm *GameManager) CloseRoom(room *model.Room, reason string) {
+	config.GetZapLogger().Info("关闭房间 " + room.ID + "，原因: " + reason)
+	room.BroadcastAll(model.Message{
+		Type: model.MsgRoomClosed,
+		Data: model.RoomClosedData{Reason: reason},
+	})
+	clients := transport.For(room)
+	for _, p := range room.Recipients() {
+		p.Connected = false
+		if clients == nil {
+			continue
+		}
+		clients.Release(p.ID)
+		clients.StopAutoPlay(p.ID)
+		// 先取消登记，读协程随后退出时不会再按掉线处理
+		conn := clients.Detach(p.ID)
+		if c, ok := conn.(*transport.Connection); ok {
+			c.Shutdown(websocket.CloseNormalClosure, "房间已关闭")
+		} else if conn != nil {
+			conn.Close()
+		}
+	}
+	room.StopTimer()
+	gm.removeRoom(room.ID)
+	gm.emit(RoomEvent{Type: RoomClosed, RoomID: room.ID, Reason: reason})
+}
+
+// hasConnected 房间里是否有在线的真人玩家或观战者，机器人不算
+func hasConnected(room *model.Room) bool {
+	for _, p := range room.Recipients() {
+		if p.Connected && !p.Bot {
+			return true
+		}
+	}
+	return false
+}
+
+// emit 把房间生命周期事件交给 OnRoomEvent
+func (gm *GameManager) emit(e RoomEvent) {
+	e.Time = time.Now()
+	if gm.OnRoomEvent != nil {
+		gm.OnRoomEvent(e)
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"goMahjong/model"
+	"goMahjong/store"
+	"goMahjong/transport"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient 记录收到的消息和是否被关闭
+type fakeClient struct {
+	received []string
+	closed   bool
+}
+
+func (c *fakeClient) Send(message model.Message) error {
+	c.received = append(c.received, message.Type)
+	return nil
+}
+
+func (c *fakeClient) Close() {
+	c.closed = true
+}
+
+func setRoomConfig(t *testing.T, values map[string]interface{}) {
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+	t.Cleanup(func() {
+		for key := range values {
+			viper.Set(key, nil)
+		}
+	})
+}
+
+// newRoomWithOwner 创建房间并加入房主，connected 表示房主是否连上了
+func newRoomWithOwner(t *testing.T, gm *GameManager, connected bool) (*model.Room, *fakeClient) {
+	room, err := gm.CreateRoom("", "")
+	require.NoError(t, err)
+	owner := model.NewPlayer("房主")
+	client := &fakeClient{}
+	room.Lock()
+	if connected {
+		transport.For(room).Attach(owner.ID, client)
+		owner.Connected = true
+	}
+	room.AddPlayer(owner)
+	room.SetOwner(owner)
+	gm.SaveRoom(room)
+	room.Unlock()
+	return room, client
+}
+
+func TestJanitorExpiresRoomsWithNobodyConnected(t *testing.T) {
+	setRoomConfig(t, map[string]interface{}{"room.idleTimeout": time.Minute})
+	st := store.NewMemory()
+	gm := NewGameManager(st)
+	events := make([]RoomEvent, 0)
+	gm.OnRoomEvent = func(e RoomEvent) { events = append(events, e) }
+
+	abandoned, _ := newRoomWithOwner(t, gm, false)
+	active, _ := newRoomWithOwner(t, gm, true)
+
+	start := time.Now()
+	assert.Zero(t, gm.Sweep(start))
+	assert.Zero(t, gm.Sweep(start.Add(59*time.Second)))
+	assert.Equal(t, 1, gm.Sweep(start.Add(time.Minute)))
+
+	assert.Nil(t, gm.GetRoom(abandoned.ID))
+	_, err := st.GetRoom(abandoned.ID)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	assert.NotNil(t, gm.GetRoom(active.ID), "有人在线的房间不关闭")
+
+	require.Len(t, events, 3)
+	assert.Equal(t, RoomCreated, events[0].Type)
+	assert.Equal(t, RoomClosed, events[2].Type)
+	assert.Equal(t, abandoned.ID, events[2].RoomID)
+	assert.Equal(t, CloseReasonIdle, events[2].Reason)
+
+	// 最后一个人断线之后重新开始计时
+	active.Lock()
+	active.Owner.Connected = false
+	active.Unlock()
+	assert.Zero(t, gm.Sweep(start.Add(2*time.Minute)))
+	assert.Equal(t, 1, gm.Sweep(start.Add(3*time.Minute)))
+	assert.Empty(t, gm.GetAllRooms())
+}
+
+func TestJanitorClosesFinishedRoomsAndDisconnectsPlayers(t *testing.T) {
+	setRoomConfig(t, map[string]interface{}{"room.finishedTimeout": 5 * time.Minute})
+	gm := NewGameManager(store.NewMemory())
+	room, client := newRoomWithOwner(t, gm, true)
+	room.Lock()
+	room.GameState = model.GameStateFinished
+	room.Unlock()
+
+	start := time.Now()
+	assert.Zero(t, gm.Sweep(start))
+	// 房间里还有动静（聊天等）时重新计时
+	room.Lock()
+	room.BroadcastAll(model.Message{Type: model.MsgChat, Data: model.ChatData{Content: "再来一局？"}})
+	room.Unlock()
+	assert.Zero(t, gm.Sweep(start.Add(4*time.Minute)))
+	assert.Zero(t, gm.Sweep(start.Add(8*time.Minute)))
+	assert.Equal(t, 1, gm.Sweep(start.Add(9*time.Minute)))
+
+	assert.Nil(t, gm.GetRoom(room.ID))
+	assert.Equal(t, model.MsgRoomClosed, client.received[len(client.received)-1])
+	assert.True(t, client.closed)
+	assert.Nil(t, transport.For(room).Conn(room.Owner.ID), "关闭后读协程不会再按掉线处理")
+}
+
+func TestCreateRoomIsLimitedInTotalAndPerAddress(t *testing.T) {
+	setRoomConfig(t, map[string]interface{}{"room.maxRooms": 2, "room.maxRoomsPerIP": 1})
+	gm := NewGameManager(store.NewMemory())
+
+	first, err := gm.CreateRoom("", "10.0.0.1")
+	require.NoError(t, err)
+	_, err = gm.CreateRoom("", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrTooManyRoomsForIP)
+	_, err = gm.CreateRoom("", "10.0.0.2")
+	require.NoError(t, err)
+	_, err = gm.CreateRoom("", "10.0.0.3")
+	assert.ErrorIs(t, err, ErrTooManyRooms)
+
+	// 房间关闭之后额度还给创建者
+	gm.RemoveRoom(first.ID)
+	_, err = gm.CreateRoom("", "10.0.0.1")
+	assert.NoError(t, err)
+}
+
+// 恢复出的房间仍然算在创建者的IP上，重启不会清空每个IP的房间数
+func TestRestoredRoomsCountTowardsTheirCreator(t *testing.T) {
+	setRoomConfig(t, map[string]interface{}{"room.maxRoomsPerIP": 1})
+	st := store.NewMemory()
+	gm := NewGameManager(st)
+
+	room, err := gm.CreateRoom("", "10.0.0.1")
+	require.NoError(t, err)
+	room.Lock()
+	owner := model.NewPlayer("房主")
+	room.AddPlayer(owner)
+	room.SetOwner(owner)
+	gm.SaveRoom(room)
+	room.Unlock()
+
+	restarted := NewGameManager(st)
+	require.Len(t, restarted.RestoreRooms(), 1)
+	_, err = restarted.CreateRoom("", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrTooManyRoomsForIP)
+	_, err = restarted.CreateRoom("", "10.0.0.2")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// AttemptLimiter 限制一段时间内加入房间的失败次数，用于防止暴力破解
+// 失败按（IP，房间）和IP分别计数：成功加入只清空该IP对这个房间的记录，
+// IP的总失败次数不因成功而清空，避免在自己的房间成功一次就能继续猜别的房间
+// 每次尝试在校验密码之前先按失败记下，成功后才撤销，同一IP并发的请求不能趁密码校验还没结束时绕过限制
+type AttemptLimiter struct {
+	maxFailures      int
+	maxFailuresPerIP int
+	window           time.Duration
+	failures         map[string][]time.Time // key 为 IP 和房间号
+	ipFailures       map[string][]time.Time // key 为 IP
+	mutex            sync.Mutex
+}
+
+// NewAttemptLimiter 创建限制器，window时间内同一IP对同一房间最多允许maxFailures次失败，
+// 同一IP总共最多允许maxFailuresPerIP次失败
+func NewAttemptLimiter(maxFailures, maxFailuresPerIP int, window time.Duration) *AttemptLimiter {
+	return &AttemptLimiter{
+		maxFailures:      maxFailures,
+		maxFailuresPerIP: maxFailuresPerIP,
+		window:           window,
+		failures:         make(map[string][]time.Time),
+		ipFailures:       make(map[string][]time.Time),
+	}
+}
+
+// Attempt 判断该IP当前是否还允许尝试加入该房间，允许时先把这次尝试记为一次失败
+// 尝试成功后调用 Succeed 撤销，其余情况不需要再做什么
+func (l *AttemptLimiter) Attempt(ip, roomID string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	key := attemptKey(ip, roomID)
+	if len(l.recent(l.failures, key, now)) >= l.maxFailures ||
+		len(l.recent(l.ipFailures, ip, now)) >= l.maxFailuresPerIP {
+		return false
+	}
+	l.failures[key] = append(l.recent(l.failures, key, now), now)
+	l.ipFailures[ip] = append(l.recent(l.ipFailures, ip, now), now)
+
+	// 顺便清理其他已经过期的记录，避免map无限增长
+	for _, records := range []map[string][]time.Time{l.failures, l.ipFailures} {
+		for k := range records {
+			if len(l.recent(records, k, now)) == 0 {
+				delete(records, k)
+			}
+		}
+	}
+	return true
+}
+
+// Succeed 尝试成功后清空该IP对该房间的失败记录，并撤销这次尝试预先记下的一次，
+// IP之前的失败次数保留到窗口期结束
+func (l *AttemptLimiter) Succeed(ip, roomID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.failures, attemptKey(ip, roomID))
+	if times := l.ipFailures[ip]; len(times) > 0 {
+		l.ipFailures[ip] = times[:len(times)-1]
+	}
+}
+
+// 返回窗口期内的失败记录，调用方需持有锁
+func (l *AttemptLimiter) recent(records map[string][]time.Time, key string, now time.Time) []time.Time {
+	times, ok := records[key]
+	if !ok {
+		return nil
+	}
+	i := 0
+	for i < len(times) && now.Sub(times[i]) > l.window {
+		i++
+	}
+	times = times[i:]
+	records[key] = times
+	return times
+}
+
+// attemptKey 失败记录的key
+func attemptKey(ip, roomID string) string {
+	return ip + "|" + roomID
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttemptLimiterCountsFailuresPerRoom(t *testing.T) {
+	l := NewAttemptLimiter(2, 100, time.Minute)
+	assert.True(t, l.Attempt("10.0.0.1", "A"))
+	assert.True(t, l.Attempt("10.0.0.1", "A"))
+	assert.False(t, l.Attempt("10.0.0.1", "A"))
+	assert.True(t, l.Attempt("10.0.0.1", "B"), "其他房间不受影响")
+	assert.True(t, l.Attempt("10.0.0.2", "A"), "其他IP不受影响")
+
+	l.Succeed("10.0.0.1", "A")
+	assert.True(t, l.Attempt("10.0.0.1", "A"))
+}
+
+// 成功加入自己的房间不会清空这个IP猜其他房间的失败次数
+func TestAttemptLimiterCapsEachAddressDespiteSuccesses(t *testing.T) {
+	l := NewAttemptLimiter(2, 3, time.Minute)
+	for _, roomID := range []string{"A", "B", "C"} {
+		assert.True(t, l.Attempt("10.0.0.1", "own"))
+		l.Succeed("10.0.0.1", "own")
+		assert.True(t, l.Attempt("10.0.0.1", roomID))
+	}
+	assert.False(t, l.Attempt("10.0.0.1", "D"))
+	assert.False(t, l.Attempt("10.0.0.1", "own"))
+	assert.True(t, l.Attempt("10.0.0.2", "D"))
+}
+
+// 还没有结果的尝试已经计入次数，并发的请求不能一起通过检查
+func TestAttemptLimiterCountsPendingAttempts(t *testing.T) {
+	l := NewAttemptLimiter(3, 100, time.Minute)
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		if l.Attempt("10.0.0.1", "A") {
+			allowed++
+		}
+	}
+	assert.Equal(t, 3, allowed)
+}
+
+func TestAttemptLimiterForgetsOldFailures(t *testing.T) {
+	l := NewAttemptLimiter(1, 1, 20*time.Millisecond)
+	assert.True(t, l.Attempt("10.0.0.1", "A"))
+	assert.False(t, l.Attempt("10.0.0.1", "A"))
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, l.Attempt("10.0.0.1", "A"))
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken 令牌格式错误或签名不匹配
+	ErrInvalidToken = errors.New("invalid session token")
+	// ErrTokenExpired 令牌已过期
+	ErrTokenExpired = errors.New("session token expired")
+)
+
+// SessionManager 签发和校验玩家的会话令牌
+// 令牌是玩家连接WebSocket的凭证，与会被广播给所有人的playerID分开，
+// 格式为 base64(负载).base64(HMAC-SHA256签名)
+type SessionManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// sessionClaims 令牌负载
+type sessionClaims struct {
+	RoomID    string `json:"r"`
+	PlayerID  string `json:"p"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// NewSessionManager 创建会话管理器，secret为空时随机生成（重启后旧令牌失效）
+func NewSessionManager(secret string, ttl time.Duration) *SessionManager {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(err)
+		}
+	}
+	return &SessionManager{secret: key, ttl: ttl}
+}
+
+// Issue 为房间中的玩家签发令牌
+func (sm *SessionManager) Issue(roomID, playerID string) string {
+	payload, _ := json.Marshal(sessionClaims{
+		RoomID:    roomID,
+		PlayerID:  playerID,
+		ExpiresAt: time.Now().Add(sm.ttl).Unix(),
+	})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(sm.sign(encoded))
+}
+
+// Verify 校验令牌，返回令牌对应的房间ID和玩家ID
+func (sm *SessionManager) Verify(token string) (roomID string, playerID string, err error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", ErrInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, sm.sign(encoded)) {
+		return "", "", ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+	var claims sessionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", "", ErrInvalidToken
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return "", "", ErrTokenExpired
+	}
+
+	return claims.RoomID, claims.PlayerID, nil
+}
+
+// 计算签名
+func (sm *SessionManager) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, sm.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
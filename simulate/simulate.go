@@ -0,0 +1,293 @@
+// Package simulate 机器人自对弈：不经过WebSocket，在进程内用固定的种子发牌，
+// 让机器人按房间的规则打完整局，统计胜率、番数分布、流局率和对局长度，
+// 同时在每一步之后检查对局状态，发现规则引擎或机器人的问题
+//
+// 模拟的房间不设置时钟，不计时也没有超时，每个房间只在自己的协程里执行命令，所以不需要加锁
+package simulate
+
+import (
+	"errors"
+	"fmt"
+	"goMahjong/bot"
+	"goMahjong/model"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// maxSteps 一局最多的决策次数，超过说明对局卡住了
+const maxSteps = 2000
+
+// Config 模拟的参数
+type Config struct {
+	Hands   int      // 模拟的局数
+	Seed    int64    // 第 i 局用 Seed+i 发牌，同样的参数总是得到同样的结果
+	Levels  []string // 每个座位机器人的难度，2到4个
+	Workers int      // 并行的协程数，小于1时为1
+}
+
+// HandResult 一局的结果
+type HandResult struct {
+	Seed      int64
+	Winner    int  // 胡牌的座位，流局为-1
+	SelfDrawn bool // 是否自摸
+	Fan       int
+	Discards  int   // 打出的牌数
+	Deltas    []int // 每个座位的得分变化
+}
+
+// Violation 模拟中发现的问题：状态不自洽、机器人的决定被规则拒绝或对局卡住
+type Violation struct {
+	Seed int64  `json:"seed"`
+	Err  string `json:"error"`
+}
+
+// Report 模拟的统计结果
+type Report struct {
+	Hands        int         `json:"hands"`
+	Levels       []string    `json:"levels"`
+	Wins         []int       `json:"wins"`     // 每个座位胡牌的局数
+	WinRates     []float64   `json:"winRates"` // 每个座位的胡牌率
+	Scores       []int       `json:"scores"`   // 每个座位的总得分
+	SelfDrawn    int         `json:"selfDrawn"`
+	Draws        int         `json:"draws"`    // 流局的局数
+	DrawRate     float64     `json:"drawRate"` // 流局率
+	Fans         map[int]int `json:"fans"`     // 番数 -> 局数
+	AvgDiscards  float64     `json:"avgDiscards"`
+	Violations   []Violation `json:"violations"`
+	totalDiscard int
+}
+
+// Run 按配置模拟多局，出问题的局记为违规并跳过
+func Run(cfg Config) (*Report, error) {
+	if len(cfg.Levels) < 2 || len(cfg.Levels) > model.MaxPlayers {
+		return nil, errors.New("座位数需要在2到" + strconv.Itoa(model.MaxPlayers) + "之间")
+	}
+	for _, level := range cfg.Levels {
+		if _, err := bot.NewStrategy(level, 0); err != nil {
+			return nil, fmt.Errorf("难度 %q: %w", level, err)
+		}
+	}
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]HandResult, cfg.Hands)
+	errs := make([]error, cfg.Hands)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = PlayHand(cfg.Seed+int64(i), cfg.Levels)
+			}
+		}()
+	}
+	for i := 0; i < cfg.Hands; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	report := &Report{
+		Levels:     cfg.Levels,
+		Wins:       make([]int, len(cfg.Levels)),
+		WinRates:   make([]float64, len(cfg.Levels)),
+		Scores:     make([]int, len(cfg.Levels)),
+		Fans:       make(map[int]int),
+		Violations: make([]Violation, 0),
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			report.Violations = append(report.Violations, Violation{Seed: cfg.Seed + int64(i), Err: errs[i].Error()})
+			continue
+		}
+		report.add(result)
+	}
+	report.finish()
+	return report, nil
+}
+
+// PlayHand 用种子发牌，让每个座位按难度打完一局
+func PlayHand(seed int64, levels []string) (HandResult, error) {
+	room, err := play(seed, levels)
+	if err != nil {
+		return HandResult{}, err
+	}
+	return result(room, seed)
+}
+
+// PlayReplay 和 PlayHand 一样打完一局，返回这一局的回放，供回放和牌谱相关的测试使用
+func PlayReplay(seed int64, levels []string) (*model.Replay, error) {
+	room, err := play(seed, levels)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewReplay(room)
+}
+
+// play 打完一局，返回结束时的房间
+// 房间没有时钟，计时器不会在另一个协程里替玩家操作，对局只由 decide 推进
+func play(seed int64, levels []string) (*model.Room, error) {
+	room, err := model.NewRoom("")
+	if err != nil {
+		return nil, err
+	}
+	room.Clock = nil
+	strategies := make([]bot.Strategy, len(levels))
+	seats := make([]model.SeatRecord, len(levels))
+	for i, level := range levels {
+		strategies[i], _ = bot.NewStrategy(level, seed*int64(model.MaxPlayers)+int64(i))
+		seats[i] = model.SeatRecord{ID: "seat" + strconv.Itoa(i), Name: "座位" + strconv.Itoa(i)}
+		player := model.NewPlayer(seats[i].Name)
+		player.ID = seats[i].ID
+		player.Bot = true
+		room.AddPlayer(player)
+	}
+	if err := room.StartGameWithDeal(model.NewDeal(seats, seed)); err != nil {
+		return nil, err
+	}
+
+	for step := 0; room.GameState == model.GameStatePlaying; step++ {
+		if step == maxSteps {
+			return nil, fmt.Errorf("%d 步之后对局仍未结束，阶段 %s", maxSteps, room.Phase)
+		}
+		if err := decide(room, strategies); err != nil {
+			return nil, err
+		}
+		if err := room.CheckInvariants(); err != nil {
+			return nil, err
+		}
+	}
+	return room, nil
+}
+
+// decide 让需要行动的机器人做一次决定并交给房间执行，模拟时没有人接收消息，执行结果直接丢弃
+func decide(room *model.Room, strategies []bot.Strategy) error {
+	cmd, err := command(room, strategies)
+	if err != nil {
+		return err
+	}
+	_, err = room.Execute(cmd)
+	return err
+}
+
+// command 需要行动的机器人做出的决定
+func command(room *model.Room, strategies []bot.Strategy) (model.Command, error) {
+	view := func(i int) *bot.View {
+		id := room.Players[i].ID
+		return &bot.View{GameSnapshotData: room.SeatView(id), PlayerID: id}
+	}
+
+	switch room.Phase {
+	case model.PhaseDingque:
+		for i, p := range room.Players {
+			if p.Missing == "" {
+				return model.DingqueCommand{PlayerID: p.ID, Suit: strategies[i].Dingque(view(i))}, nil
+			}
+		}
+	case model.PhaseClaim:
+		for i, p := range room.Players {
+			if v := view(i); len(v.Options) > 0 {
+				return model.ActionCommand{PlayerID: p.ID, Action: strategies[i].Claim(v)}, nil
+			}
+		}
+	case model.PhaseDiscard:
+		i := room.CurrentPlayerIndex
+		v := view(i)
+		if len(v.Options) > 0 {
+			if action, tile := strategies[i].Turn(v); action != "" {
+				var tiles []string
+				if tile != "" {
+					tiles = []string{tile}
+				}
+				return model.ActionCommand{PlayerID: v.PlayerID, Action: action, Tiles: tiles}, nil
+			}
+		}
+		return model.PlayTileCommand{PlayerID: v.PlayerID, Tile: strategies[i].Discard(v)}, nil
+	}
+	return nil, fmt.Errorf("阶段 %s 没有需要行动的玩家", room.Phase)
+}
+
+// result 从事件日志中取出本局的结果，同时检查得分守恒
+func result(room *model.Room, seed int64) (HandResult, error) {
+	r := HandResult{Seed: seed, Winner: -1, Deltas: make([]int, len(room.Players))}
+	index := make(map[string]int)
+	for i, p := range room.Players {
+		index[p.ID] = i
+	}
+
+	total := 0
+	for _, e := range room.Events {
+		switch e.Type {
+		case model.EventDiscard:
+			r.Discards++
+		case model.EventWin:
+			r.Winner = index[e.PlayerID]
+			r.SelfDrawn = e.Win.SelfDrawn
+			r.Fan = e.Win.Fan
+		case model.EventSettlement:
+			for id, delta := range e.Settlement.Deltas {
+				r.Deltas[index[id]] = delta
+				total += delta
+			}
+		}
+	}
+	if total != 0 {
+		return r, fmt.Errorf("得分变化之和为 %d", total)
+	}
+	return r, nil
+}
+
+func (r *Report) add(h HandResult) {
+	r.Hands++
+	r.totalDiscard += h.Discards
+	for i, delta := range h.Deltas {
+		r.Scores[i] += delta
+	}
+	if h.Winner < 0 {
+		r.Draws++
+		return
+	}
+	r.Wins[h.Winner]++
+	r.Fans[h.Fan]++
+	if h.SelfDrawn {
+		r.SelfDrawn++
+	}
+}
+
+func (r *Report) finish() {
+	if r.Hands == 0 {
+		return
+	}
+	n := float64(r.Hands)
+	for i, wins := range r.Wins {
+		r.WinRates[i] = float64(wins) / n
+	}
+	r.DrawRate = float64(r.Draws) / n
+	r.AvgDiscards = float64(r.totalDiscard) / n
+}
+
+// String 文本格式的报告
+func (r *Report) String() string {
+	s := fmt.Sprintf("共 %d 局，流局 %d 局（%.1f%%），自摸 %d 局，平均每局打出 %.1f 张牌\n",
+		r.Hands, r.Draws, 100*r.DrawRate, r.SelfDrawn, r.AvgDiscards)
+	for i, level := range r.Levels {
+		s += fmt.Sprintf("座位%d %-6s 胡牌 %d 局（%.1f%%），总得分 %d\n", i, level, r.Wins[i], 100*r.WinRates[i], r.Scores[i])
+	}
+	fans := make([]int, 0, len(r.Fans))
+	for fan := range r.Fans {
+		fans = append(fans, fan)
+	}
+	sort.Ints(fans)
+	for _, fan := range fans {
+		s += fmt.Sprintf("%d番 %d 局\n", fan, r.Fans[fan])
+	}
+	s += fmt.Sprintf("违规 %d 局\n", len(r.Violations))
+	for _, v := range r.Violations {
+		s += fmt.Sprintf("  种子 %d: %s\n", v.Seed, v.Err)
+	}
+	return s
+}
@@ -0,0 +1,71 @@
+package simulate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfPlayHasNoViolations(t *testing.T) {
+	report, err := Run(Config{Hands: 40, Seed: 1, Levels: []string{"normal", "normal", "hard", "easy"}, Workers: 4})
+	require.NoError(t, err)
+	assert.Empty(t, report.Violations)
+	assert.Equal(t, 40, report.Hands)
+
+	wins := report.Draws
+	total := 0
+	for i, w := range report.Wins {
+		wins += w
+		total += report.Scores[i]
+	}
+	assert.Equal(t, report.Hands, wins, "每局要么有人胡牌要么流局")
+	assert.Zero(t, total, "输赢之和为0")
+	assert.Greater(t, report.AvgDiscards, 0.0)
+}
+
+func TestSelfPlayIsReproducible(t *testing.T) {
+	cfg := Config{Hands: 10, Seed: 42, Levels: []string{"easy", "normal", "hard"}, Workers: 3}
+	first, err := Run(cfg)
+	require.NoError(t, err)
+	second, err := Run(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	hand, err := PlayHand(42, cfg.Levels)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), hand.Seed)
+}
+
+// 服务端的超时设置不影响模拟，计时器不会和 decide 同时操作房间
+func TestSelfPlayIgnoresServerTimeouts(t *testing.T) {
+	levels := []string{"easy", "normal", "hard", "easy"}
+	want, err := PlayHand(7, levels)
+	require.NoError(t, err)
+
+	keys := []string{"game.dingqueTimeout", "game.turnTimeout", "game.claimTimeout"}
+	for _, key := range keys {
+		viper.Set(key, time.Nanosecond)
+	}
+	t.Cleanup(func() {
+		for _, key := range keys {
+			viper.Set(key, nil)
+		}
+	})
+
+	room, err := play(7, levels)
+	require.NoError(t, err)
+	assert.Nil(t, room.Clock)
+	got, err := result(room, 7)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestRunRejectsBadSeats(t *testing.T) {
+	_, err := Run(Config{Hands: 1, Levels: []string{"normal"}})
+	assert.Error(t, err)
+	_, err = Run(Config{Hands: 1, Levels: []string{"normal", "impossible"}})
+	assert.Error(t, err)
+}
@@ -28,7 +28,9 @@ function createRoom() {
     .then(data => {
         // 保存玩家ID到本地存储
         localStorage.setItem('playerID', data.playerID);
-        
+        // 保存会话令牌，连接WebSocket时使用
+        localStorage.setItem('sessionToken', data.token);
+        localStorage.removeItem('spectator');
         
         // 跳转到房间页面
         window.location.href = `/room/${data.roomID}`;
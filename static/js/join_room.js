@@ -8,6 +8,10 @@ document.addEventListener('DOMContentLoaded', function() {
         // 如果URL中有房间ID，自动填充
         document.getElementById('roomID').value = roomID;
     }
+    // 从房间列表点观战进来时把焦点放在观战按钮上
+    if (urlParams.get('spectate')) {
+        document.getElementById('spectateBtn').focus();
+    }
 });
 
 function joinRoom() {
@@ -34,20 +38,32 @@ function joinRoom() {
         body: JSON.stringify({
             playerName: playerName,
             roomID: roomID,
-            password: password
+            password: password,
+            session: localStorage.getItem('sessionToken') || ''
         })
     })
     .then(response => {
         if (!response.ok) {
             return response.json().then(err => {
+                // 对局进行中不能加入，可以改为观战
+                if (err.spectate && confirm(`${err.error}，要观战吗？`)) {
+                    spectateRoom();
+                    return null;
+                }
                 throw new Error(err.error || '加入房间失败');
             });
         }
         return response.json();
     })
     .then(data => {
+        if (!data) {
+            return;
+        }
         // 保存玩家ID到本地存储
         localStorage.setItem('playerID', data.playerID);
+        // 保存会话令牌，连接WebSocket时使用
+        localStorage.setItem('sessionToken', data.token);
+        localStorage.removeItem('spectator');
         
         // 跳转到房间页面
         window.location.href = `/room/${data.roomID}`;
@@ -56,4 +72,58 @@ function joinRoom() {
         console.error('Error:', error);
         alert(error.message);
     });
-} 
\ No newline at end of file
+} 
+
+// 观战房间，观战者不占座位，勾选教练视角并填写教练密钥时可以看到延迟公开的所有人手牌
+// 同时带上浏览器里已有的会话令牌，服务端据此拒绝座位上的玩家给自己的房间当教练
+function spectateRoom() {
+    const playerName = document.getElementById('playerName').value.trim();
+    const roomID = document.getElementById('roomID').value.trim();
+    const password = document.getElementById('password').value;
+    const coach = document.getElementById('coach').checked;
+    
+    if (!playerName) {
+        alert('请输入您的名字');
+        return;
+    }
+    
+    if (!roomID) {
+        alert('请输入房间号');
+        return;
+    }
+    
+    fetch('/api/room/spectate', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({
+            playerName: playerName,
+            roomID: roomID,
+            password: password,
+            coach: coach,
+            coachKey: coach ? document.getElementById('coachKey').value : '',
+            session: localStorage.getItem('sessionToken') || ''
+        })
+    })
+    .then(response => {
+        if (!response.ok) {
+            return response.json().then(err => {
+                throw new Error(err.error || '观战失败');
+            });
+        }
+        return response.json();
+    })
+    .then(data => {
+        localStorage.setItem('playerID', data.playerID);
+        localStorage.setItem('sessionToken', data.token);
+        // 房间页面根据这个标记切换到观战界面
+        localStorage.setItem('spectator', '1');
+        
+        window.location.href = `/room/${data.roomID}`;
+    })
+    .catch(error => {
+        console.error('Error:', error);
+        alert(error.message);
+    });
+}
\ No newline at end of file
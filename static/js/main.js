@@ -26,6 +26,11 @@ function loadRoomList() {
                     const roomCard = document.createElement('div');
                     roomCard.className = 'room-card';
                     roomCard.onclick = function() {
+                        // 对局进行中不能加入，只能观战
+                        if (room.gameState === 'playing') {
+                            window.location.href = `/join?room=${room.id}&spectate=1`;
+                            return;
+                        }
                         joinRoom(room.id);
                     };
                     
@@ -36,11 +41,17 @@ function loadRoomList() {
                         <div class="room-id">房间号: ${room.id}</div>
                         <div class="room-info">
                             <div class="room-players">玩家: ${room.playerCount}/4</div>
+                            <div class="room-spectators">观战: ${room.spectatorCount || 0}</div>
                             <div class="room-status ${statusClass}">${statusText}</div>
                         </div>
                         <div class="room-owner">房主: ${room.owner.name}</div>
                         <button class="room-join-btn">加入房间</button>
+                        <button class="room-join-btn room-spectate-btn">观战</button>
                     `;
+                    roomCard.querySelector('.room-spectate-btn').onclick = function(e) {
+                        e.stopPropagation();
+                        window.location.href = `/join?room=${room.id}&spectate=1`;
+                    };
                     
                     roomList.appendChild(roomCard);
                 });
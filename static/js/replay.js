@@ -0,0 +1,313 @@
+// 回放页面：按顺序应用回放中的事件，显示每一步所有人的手牌
+// 事件的含义与服务端 model/event.go 中的 apply 一致
+
+let replay = null; // 回放文件
+let states = []; // 每一步之后的牌局状态，states[0] 是发牌后
+let position = 0; // 当前显示的步数
+let playTimer = null; // 自动播放的计时器
+
+const suitImages = { t: 'tiao', p: 'tong', w: 'wan' };
+const suitNames = { t: '条', p: '筒', w: '万' };
+const meldNames = { peng: '碰', ming_gang: '明杠', an_gang: '暗杠', bu_gang: '补杠' };
+
+document.addEventListener('DOMContentLoaded', function() {
+    document.getElementById('firstBtn').onclick = function() { show(0); };
+    document.getElementById('prevBtn').onclick = function() { show(position - 1); };
+    document.getElementById('nextBtn').onclick = function() { show(position + 1); };
+    document.getElementById('lastBtn').onclick = function() { show(states.length - 1); };
+    document.getElementById('playBtn').onclick = togglePlay;
+    document.getElementById('exportLink').href = `/api/replays/${replayID}/mjai`;
+    document.getElementById('importFile').onchange = importMJAI;
+
+    fetch(`/api/replays/${replayID}`)
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('回放不存在');
+            }
+            return response.json();
+        })
+        .then(data => {
+            replay = data;
+            states = buildStates(data.events);
+            document.getElementById('replayRoom').textContent = data.roomID ? `房间 ${data.roomID}` : '导入的牌谱';
+            document.getElementById('replayRuleset').textContent =
+                `规则: ${data.ruleset.name}  底分: ${data.ruleset.baseScore}  封顶: ${data.ruleset.maxFan}番  种子: ${data.seed}`;
+            show(0);
+        })
+        .catch(error => {
+            document.getElementById('replayEvent').textContent = error.message;
+        });
+});
+
+// 导入MJAI牌谱，导入后打开第一局
+function importMJAI(event) {
+    const file = event.target.files[0];
+    if (!file) {
+        return;
+    }
+    file.text()
+        .then(text => fetch('/api/replays/import', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/x-ndjson' },
+            body: text
+        }))
+        .then(response => response.json())
+        .then(data => {
+            if (data.error) {
+                throw new Error(data.error);
+            }
+            if (data.replayIDs.length > 1) {
+                alert(`导入了 ${data.replayIDs.length} 局，先打开第一局`);
+            }
+            window.location.href = `/replay/${data.replayIDs[0]}`;
+        })
+        .catch(error => {
+            alert(error.message);
+        });
+}
+
+// 依次应用事件，记录每一步之后的状态
+function buildStates(events) {
+    const result = [];
+    let state = null;
+    events.forEach(event => {
+        state = state ? clone(state) : null;
+        state = applyEvent(state, event);
+        result.push(state);
+    });
+    return result;
+}
+
+function clone(state) {
+    return JSON.parse(JSON.stringify(state));
+}
+
+// 应用一个事件
+function applyEvent(state, event) {
+    if (event.type === 'deal') {
+        const deal = event.deal;
+        return {
+            event: event,
+            dealer: deal.dealer,
+            wall: deal.wall.length,
+            seats: deal.seats.map(seat => ({
+                id: seat.id,
+                name: seat.name,
+                score: seat.score,
+                tiles: sortTiles(deal.hands[seat.id].slice()),
+                discards: [],
+                melds: [],
+                missing: '',
+                drawn: ''
+            }))
+        };
+    }
+
+    state.event = event;
+    state.seats.forEach(seat => { seat.drawn = ''; });
+    const seat = state.seats.find(s => s.id === event.playerID);
+    switch (event.type) {
+        case 'dingque':
+            seat.missing = event.suit;
+            break;
+        case 'draw':
+            state.wall--;
+            seat.drawn = event.tile;
+            seat.tiles.push(event.tile);
+            break;
+        case 'discard':
+            removeTiles(seat.tiles, event.tile, 1);
+            sortTiles(seat.tiles);
+            seat.discards.push(event.tile);
+            break;
+        case 'claim':
+            takeDiscard(state, event.from);
+            removeTiles(seat.tiles, event.tile, 2);
+            seat.melds.push({ kind: 'peng', tile: event.tile });
+            break;
+        case 'gang':
+            if (event.kind === 'ming_gang') {
+                takeDiscard(state, event.from);
+                removeTiles(seat.tiles, event.tile, 3);
+                seat.melds.push({ kind: event.kind, tile: event.tile });
+            } else if (event.kind === 'an_gang') {
+                removeTiles(seat.tiles, event.tile, 4);
+                seat.melds.push({ kind: event.kind, tile: event.tile });
+            } else {
+                removeTiles(seat.tiles, event.tile, 1);
+                const meld = seat.melds.find(m => m.kind === 'peng' && m.tile === event.tile);
+                if (meld) {
+                    meld.kind = event.kind;
+                }
+            }
+            sortTiles(seat.tiles);
+            break;
+        case 'win':
+            if (event.from) {
+                takeDiscard(state, event.from);
+                seat.tiles.push(event.tile);
+            }
+            seat.won = true;
+            break;
+        case 'settlement':
+            state.seats.forEach(s => {
+                s.delta = event.settlement.deltas[s.id] || 0;
+                s.score += s.delta;
+            });
+            break;
+    }
+    return state;
+}
+
+function takeDiscard(state, fromID) {
+    const from = state.seats.find(s => s.id === fromID);
+    if (from) {
+        from.discards.pop();
+    }
+}
+
+function removeTiles(tiles, tile, count) {
+    for (let i = 0; i < count; i++) {
+        const index = tiles.indexOf(tile);
+        if (index !== -1) {
+            tiles.splice(index, 1);
+        }
+    }
+}
+
+// 按条、筒、万和点数排序，与 rules.SortTiles 一致
+function sortTiles(tiles) {
+    const order = tile => 'tpw'.indexOf(tile[1]) * 9 + Number(tile[0]);
+    return tiles.sort((a, b) => order(a) - order(b));
+}
+
+// 显示第n步
+function show(n) {
+    if (states.length === 0) {
+        return;
+    }
+    position = Math.max(0, Math.min(n, states.length - 1));
+    const state = states[position];
+
+    document.getElementById('replayProgress').textContent = `${position + 1} / ${states.length}`;
+    document.getElementById('replayEvent').textContent = describeEvent(state, state.event);
+    document.getElementById('replayWall').textContent = state.wall;
+    document.getElementById('prevBtn').disabled = position === 0;
+    document.getElementById('nextBtn').disabled = position === states.length - 1;
+    if (position === states.length - 1) {
+        stopPlay();
+    }
+
+    const seatsElement = document.getElementById('replaySeats');
+    seatsElement.innerHTML = '';
+    state.seats.forEach(seat => {
+        seatsElement.appendChild(renderSeat(state, seat));
+    });
+}
+
+// 渲染一个座位：名字、定缺、分数、副露、手牌和弃牌
+function renderSeat(state, seat) {
+    const seatElement = document.createElement('div');
+    seatElement.className = 'replay-seat';
+    if (state.event.playerID === seat.id) {
+        seatElement.className += ' active';
+    }
+
+    const info = document.createElement('div');
+    info.className = 'player-info';
+    let text = seat.name;
+    if (seat.id === state.dealer) {
+        text += '（庄）';
+    }
+    if (seat.missing) {
+        text += ` 缺${suitNames[seat.missing]}`;
+    }
+    text += `  ${seat.score}分`;
+    if (seat.delta !== undefined) {
+        text += `（${seat.delta >= 0 ? '+' : ''}${seat.delta}）`;
+    }
+    if (seat.won) {
+        text += '  胡';
+    }
+    info.textContent = text;
+    seatElement.appendChild(info);
+
+    const hand = document.createElement('div');
+    hand.className = 'replay-tiles';
+    seat.melds.forEach(meld => {
+        const count = meld.kind === 'peng' ? 3 : 4;
+        for (let i = 0; i < count; i++) {
+            hand.appendChild(tileImage(meld.tile, 'meld'));
+        }
+    });
+    seat.tiles.forEach(tile => {
+        hand.appendChild(tileImage(tile, tile === seat.drawn ? 'drawn' : ''));
+    });
+    seatElement.appendChild(hand);
+
+    const discards = document.createElement('div');
+    discards.className = 'replay-tiles discards';
+    seat.discards.forEach(tile => {
+        discards.appendChild(tileImage(tile, ''));
+    });
+    seatElement.appendChild(discards);
+
+    return seatElement;
+}
+
+function tileImage(tile, className) {
+    const img = document.createElement('img');
+    img.src = `/static/images/${suitImages[tile[1]]}${tile[0]}.gif`;
+    img.alt = tile;
+    img.className = className;
+    return img;
+}
+
+// 事件的文字说明
+function describeEvent(state, event) {
+    const name = id => (state.seats.find(s => s.id === id) || {}).name || '玩家';
+    const tile = t => `${t[0]}${suitNames[t[1]]}`;
+    switch (event.type) {
+        case 'deal':
+            return `发牌，${name(event.deal.dealer)}坐庄`;
+        case 'dingque':
+            return `${name(event.playerID)} 定缺${suitNames[event.suit]}`;
+        case 'draw':
+            return `${name(event.playerID)} 摸牌 ${tile(event.tile)}`;
+        case 'discard':
+            return `${name(event.playerID)} 打出 ${tile(event.tile)}`;
+        case 'claim':
+            return `${name(event.playerID)} 碰 ${name(event.from)} 的 ${tile(event.tile)}`;
+        case 'gang':
+            return `${name(event.playerID)} ${meldNames[event.kind]} ${tile(event.tile)}`;
+        case 'win': {
+            const how = event.win.selfDrawn ? '自摸' : `胡 ${name(event.from)} 的`;
+            return `${name(event.playerID)} ${how} ${tile(event.tile)}，${event.win.fan}番（${event.win.patterns.join('、')}）`;
+        }
+        case 'settlement':
+            return '结算';
+        default:
+            return event.type;
+    }
+}
+
+// 自动播放
+function togglePlay() {
+    if (playTimer) {
+        stopPlay();
+        return;
+    }
+    if (position === states.length - 1) {
+        show(0);
+    }
+    document.getElementById('playBtn').textContent = '暂停';
+    playTimer = setInterval(function() { show(position + 1); }, 800);
+}
+
+function stopPlay() {
+    if (playTimer) {
+        clearInterval(playTimer);
+        playTimer = null;
+    }
+    document.getElementById('playBtn').textContent = '播放';
+}
@@ -1,14 +1,29 @@
 // 房间页面的JavaScript
+const PROTOCOL_VERSION = 1; // 客户端支持的最高协议版本
+let protocolVersion = PROTOCOL_VERSION; // 与服务端协商后的协议版本
 let socket;
+let lastSeq = 0; // 收到的最后一个房间消息序号
+let resyncPending = false; // 是否已经请求补发
 let myTiles = [];
 let selectedTileIndex = -1;
 let isMyTurn = false;
 let gameState = 'waiting';
 let players = []; // 存储所有玩家信息
 let myInfo = null; // 存储自己的信息
+let myMelds = []; // 自己碰杠的牌
+let myMissing = ''; // 自己定缺的花色
+let autoPlaying = false; // 自己是否托管中
+let countdownTimer = null; // 出牌、响应的倒计时
+let claimTile = ''; // 等待响应的那张牌，为空表示不在响应阶段
+let shutdownTimer = null; // 停服倒计时
+let roomClosed = false; // 房间已被清理，不再重连
 
 // 页面加载完成后执行
 document.addEventListener('DOMContentLoaded', function() {
+    if (spectating) {
+        document.body.classList.add('spectating');
+    }
+
     // 连接WebSocket
     connectWebSocket();
     
@@ -28,6 +43,16 @@ document.addEventListener('DOMContentLoaded', function() {
         }
         startGame();
     });
+
+    // 设置添加机器人按钮事件
+    document.getElementById('addBotBtn').addEventListener('click', function() {
+        sendMessage('add_bot', { level: document.getElementById('botLevel').value });
+    });
+    
+    // 设置托管按钮事件
+    document.getElementById('autoPlayBtn').addEventListener('click', function() {
+        sendMessage('auto_play', { enabled: !autoPlaying });
+    });
     
     // 设置邀请按钮事件
     document.querySelectorAll('.invite-btn').forEach(btn => {
@@ -45,10 +70,13 @@ document.addEventListener('DOMContentLoaded', function() {
     });
 
 
-    // 监听页面关闭事件
-    window.addEventListener('beforeunload', function(e) {
-        e.preventDefault();
-        userConfirmedLeave();
+    // 监听页面关闭事件：只断开连接，不发送离开消息
+    // 对局中刷新页面时服务端会保留座位，重新连接后恢复对局
+    window.addEventListener('beforeunload', function() {
+        if (socket) {
+            socket.onclose = null;
+            socket.close();
+        }
     });
 
   
@@ -75,13 +103,17 @@ function userConfirmedLeave() {
 // 连接WebSocket
 function connectWebSocket() {
     const wsProtocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-    const wsUrl = `${wsProtocol}//${window.location.host}/ws/${roomID}?playerID=${playerID}`;
+    const wsUrl = `${wsProtocol}//${window.location.host}/ws/${roomID}?v=${PROTOCOL_VERSION}`;
     
-    socket = new WebSocket(wsUrl);
+    // 会话令牌通过子协议携带，不出现在URL和访问日志里；服务端选中 mahjong.json
+    socket = new WebSocket(wsUrl, ['mahjong.json', 'mahjong.token.' + sessionToken]);
     
     socket.onopen = function() {
         console.log('WebSocket连接已建立');
         addChatMessage('系统', '已连接到游戏服务器');
+        // 服务器还在停服时会重新发送倒计时
+        clearInterval(shutdownTimer);
+        document.getElementById('shutdownNotice').style.display = 'none';
     };
     // 这行代码是一个 WebSocket 客户端 的事件处理程序，用于接收来自 WebSocket 服务器发送的消息。。
     socket.onmessage = function(event) {
@@ -89,12 +121,19 @@ function connectWebSocket() {
         handleMessage(message);
     };
     
-    socket.onclose = function() {
+    socket.onclose = function(event) {
         console.log('WebSocket连接已关闭');
-        addChatMessage('系统', '与游戏服务器的连接已断开');
+        // 1001 表示服务器停止，重启后房间会恢复
+        if (event.code === 1001) {
+            addChatMessage('系统', '服务器正在重启，稍后自动重连');
+        } else {
+            addChatMessage('系统', '与游戏服务器的连接已断开');
+        }
         
-        // 尝试重新连接
-        setTimeout(connectWebSocket, 3000);
+        // 尝试重新连接，房间已关闭时不再重连
+        if (!roomClosed) {
+            setTimeout(connectWebSocket, 3000);
+        }
     };
     
     socket.onerror = function(error) {
@@ -108,6 +147,8 @@ function sendMessage(type, data) {
     if (socket && socket.readyState === WebSocket.OPEN) {
         socket.send(JSON.stringify({
             type: type,
+            v: protocolVersion,
+            seq: lastSeq,
             data: data
         }));
     } else {
@@ -116,11 +157,64 @@ function sendMessage(type, data) {
     }
 }
 
+// 不占用序号的消息，序号表示它反映的状态
+const DIRECT_MESSAGES = ['welcome', 'error', 'room_info', 'game_snapshot', 'coach_view'];
+// 只发给部分玩家的提示，也不占用序号，但不是完整的状态
+const PROMPT_MESSAGES = ['claim_window'];
+
+// 检查消息序号，发现断档时请求补发；返回false表示该消息不需要处理
+function checkSequence(message) {
+    if (!message.seq) {
+        return true;
+    }
+    if (DIRECT_MESSAGES.includes(message.type)) {
+        lastSeq = Math.max(lastSeq, message.seq);
+        resyncPending = false;
+        return true;
+    }
+    if (PROMPT_MESSAGES.includes(message.type)) {
+        if (message.seq > lastSeq) {
+            // 提示之前的消息还没收到，补发的消息最后会带上这条提示
+            if (!resyncPending) {
+                resyncPending = true;
+                sendMessage('resync', { afterSeq: lastSeq });
+            }
+            return false;
+        }
+        return true;
+    }
+    if (message.seq <= lastSeq) {
+        // 重复收到（补发时可能出现），忽略
+        return false;
+    }
+    if (message.seq > lastSeq + 1) {
+        // 中间有消息没收到，请求补发，补发的消息里包含这一条
+        console.warn(`消息序号断档: ${lastSeq} -> ${message.seq}`);
+        if (!resyncPending) {
+            resyncPending = true;
+            sendMessage('resync', { afterSeq: lastSeq });
+        }
+        return false;
+    }
+    lastSeq = message.seq;
+    resyncPending = false;
+    return true;
+}
+
 // 处理收到的消息
 function handleMessage(message) {
     console.log('收到消息:', message);
+    if (!checkSequence(message)) {
+        return;
+    }
     
     switch (message.type) {
+        case 'welcome':
+            protocolVersion = message.data.protocolVersion;
+            break;
+        case 'error':
+            handleError(message.data);
+            break;
         case 'room_info':
             handleRoomInfo(message.data);
             break;
@@ -133,12 +227,18 @@ function handleMessage(message) {
         case 'new_owner':
             handleNewOwner(message.data);
             break;
+        case 'connection_status':
+            handleConnectionStatus(message.data);
+            break;
         case 'chat':
             handleChat(message.data);
             break;
         case 'game_started':
             handleGameStarted(message.data);
             break;
+        case 'game_snapshot':
+            handleGameSnapshot(message.data);
+            break;
         case 'tile_played':
             handleTilePlayed(message.data);
             break;
@@ -148,17 +248,72 @@ function handleMessage(message) {
         case 'turn_changed':
             handleTurnChanged(message.data);
             break;
-        case 'action_required':
-            handleActionRequired(message.data);
+        case 'dingque':
+            handleDingque(message.data);
+            break;
+        case 'claim_window':
+            handleClaimWindow(message.data);
+            break;
+        case 'meld':
+            handleMeld(message.data);
             break;
         case 'game_over':
             handleGameOver(message.data);
             break;
+        case 'server_shutdown':
+            handleServerShutdown(message.data);
+            break;
+        case 'room_closed':
+            handleRoomClosed(message.data);
+            break;
+        case 'spectators':
+            updateSpectatorCount(message.data.count);
+            break;
+        case 'coach_view':
+            handleCoachView(message.data);
+            break;
+        case 'auto_play':
+            handleAutoPlay(message.data);
+            break;
         default:
             console.log('未知消息类型:', message.type);
     }
 }
 
+// 处理停服通知，显示倒计时
+// 处理房间关闭：房间太久没有人在线或打完之后太久没有再开局，被服务器清理
+function handleRoomClosed(data) {
+    roomClosed = true;
+    startCountdown(0, 0);
+    const reason = data.reason === 'finished' ? '对局结束后长时间没有开始新的一局' : '长时间没有玩家在线';
+    addChatMessage('系统', `${reason}，房间已关闭。<a href="/">返回大厅</a>`);
+}
+
+function handleServerShutdown(data) {
+    let seconds = data.seconds;
+    const notice = document.getElementById('shutdownNotice');
+    addChatMessage('系统', `服务器将在 ${seconds} 秒后重启，不能再开始新的一局，重启后可以继续未完成的对局`);
+
+    clearInterval(shutdownTimer);
+    notice.style.display = 'inline';
+    notice.textContent = `服务器 ${seconds} 秒后重启`;
+    shutdownTimer = setInterval(function() {
+        seconds--;
+        if (seconds <= 0) {
+            clearInterval(shutdownTimer);
+            notice.textContent = '服务器重启中';
+            return;
+        }
+        notice.textContent = `服务器 ${seconds} 秒后重启`;
+    }, 1000);
+}
+
+// 处理服务端返回的错误
+function handleError(data) {
+    console.warn('请求失败:', data);
+    addChatMessage('系统', data.message);
+}
+
 // 处理房间信息
 function handleRoomInfo(data) {
     console.log('收到房间信息:', data); // 添加调试日志
@@ -176,6 +331,7 @@ function handleRoomInfo(data) {
     
     // 更新游戏状态
     document.getElementById('gameStatus').textContent = getGameStateText(gameState);
+    updateSpectatorCount(data.spectators);
     
     // 更新玩家列表
     updatePlayerList(players, owner);
@@ -187,6 +343,7 @@ function handleRoomInfo(data) {
     if (owner && owner.id === playerID && gameState === 'waiting') {
         document.getElementById('startGameBtn').style.display = 'block';
     }
+    updateAddBotButton();
     
     // 如果游戏已经开始，显示操作按钮
     if (gameState === 'playing') {
@@ -200,7 +357,7 @@ function handleRoomInfo(data) {
     }
     
     // 添加系统消息
-    addChatMessage('系统', '已加入房间');
+    addChatMessage('系统', spectating ? '正在观战' : '已加入房间');
 }
 
 // 处理玩家加入
@@ -219,6 +376,7 @@ function handlePlayerJoined(data) {
         
         // 更新麻将桌上的玩家位置
         updateTablePlayers();
+        updateAddBotButton();
         
         // 添加系统消息
         addChatMessage('系统', `${player.name} 加入了房间`);
@@ -242,6 +400,7 @@ function handlePlayerLeft(data) {
         
         // 更新麻将桌上的玩家位置
         updateTablePlayers();
+        updateAddBotButton();
         
         // 添加系统消息
         addChatMessage('系统', `${playerName} 离开了房间`);
@@ -266,20 +425,106 @@ function handleNewOwner(data) {
     } else {
         document.getElementById('startGameBtn').style.display = 'none';
     }
+    updateAddBotButton();
     
     // 添加系统消息
     const ownerName = players.find(p => p.id === ownerID)?.name || '未知玩家';
     addChatMessage('系统', `${ownerName} 成为了新房主`);
 }
 
+// 处理玩家在线状态变化
+function handleConnectionStatus(data) {
+    const player = players.find(p => p.id === data.playerID);
+    if (!player) {
+        return;
+    }
+    player.connected = data.connected;
+    
+    // 更新玩家列表
+    updatePlayerList(players, myInfo);
+    
+    // 添加系统消息
+    if (data.connected) {
+        addChatMessage('系统', `${player.name} 已上线`);
+    } else if (data.reason === 'left') {
+        addChatMessage('系统', `${player.name} 离开了房间`);
+    } else {
+        addChatMessage('系统', `${player.name} 已掉线`);
+    }
+}
+
+// 处理托管状态变化
+function handleAutoPlay(data) {
+    const player = players.find(p => p.id === data.playerID);
+    if (!player) {
+        return;
+    }
+    player.autoPlay = data.enabled;
+    if (data.playerID === playerID) {
+        autoPlaying = data.enabled;
+        updateAutoPlayButton();
+    }
+    
+    // 更新玩家列表
+    updatePlayerList(players, myInfo);
+    
+    // 添加系统消息
+    if (!data.enabled) {
+        addChatMessage('系统', `${player.name} 取消了托管`);
+    } else if (data.reason === 'disconnected') {
+        addChatMessage('系统', `${player.name} 掉线，由机器人托管`);
+    } else if (data.reason === 'left') {
+        addChatMessage('系统', `${player.name} 的座位由机器人托管到本局结束`);
+    } else {
+        addChatMessage('系统', `${player.name} 开始托管`);
+    }
+}
+
+// 显示当前阶段的倒计时，基础时间用完后显示时间银行，seconds 为0时隐藏
+function startCountdown(seconds, timeBank) {
+    clearInterval(countdownTimer);
+    const element = document.getElementById('countdown');
+    let left = seconds || 0;
+    let bank = timeBank || 0;
+    const render = () => {
+        element.textContent = left > 0 ? `剩余 ${left} 秒` : `时间银行 ${bank} 秒`;
+    };
+    if (left <= 0) {
+        element.style.display = 'none';
+        return;
+    }
+    element.style.display = 'inline';
+    render();
+    countdownTimer = setInterval(() => {
+        if (left > 0) {
+            left--;
+        } else {
+            bank--;
+        }
+        if (left <= 0 && bank <= 0) {
+            clearInterval(countdownTimer);
+            element.style.display = 'none';
+            return;
+        }
+        render();
+    }, 1000);
+}
+
+// 对局中显示托管按钮
+function updateAutoPlayButton() {
+    const button = document.getElementById('autoPlayBtn');
+    button.style.display = gameState === 'playing' ? 'block' : 'none';
+    button.textContent = autoPlaying ? '取消托管' : '托管';
+}
+
 // 处理聊天消息
 function handleChat(data) {
     const playerID = data.playerID;
     const playerName = data.playerName;
     const content = data.content;
     
-    // 添加聊天消息
-    addChatMessage(playerName, content);
+    // 添加聊天消息，观战者的名字后面加上标识
+    addChatMessage(data.spectator ? `${playerName} (观战)` : playerName, content);
 }
 
 // 添加聊天消息
@@ -325,12 +570,16 @@ function updatePlayerList(players, owner) {
         // 如果是自己，只显示名字和"(我)"标识
         if (player.id === playerID) {
             li.innerHTML = `${player.name} (我) 
-             ${owner && player.id === owner.id ? '<span class="owner-tag">房主</span>' : ''}`;
+             ${owner && player.id === owner.id ? '<span class="owner-tag">房主</span>' : ''}
+             ${player.autoPlay ? '<span class="auto-play-tag">托管</span>' : ''}`;
         } else {
-            // 如果是其他玩家，且是房主，显示房主标签
+            // 如果是其他玩家，且是房主，显示房主标签；掉线的玩家显示离线标签
             li.innerHTML = `
                 ${player.name}
                 ${owner && player.id === owner.id ? '<span class="owner-tag">房主</span>' : ''}
+                ${player.isBot ? `<span class="bot-tag">机器人·${getBotLevelText(player.botLevel)}</span>` : ''}
+                ${player.connected === false ? '<span class="offline-tag">离线</span>' : ''}
+                ${player.autoPlay ? '<span class="auto-play-tag">托管</span>' : ''}
             `;
         }
         
@@ -355,13 +604,18 @@ function updateTablePlayers() {
         tilesElement.classList.add('hidden');
     });
     
-    // 设置自己的位置
+    // 设置自己的位置，观战时下方是第一个玩家
+    const bottomID = spectating && players.length > 0 ? players[0].id : playerID;
     const bottomElement = document.getElementById('bottomPlayer');
     const bottomNameElement = bottomElement.querySelector('.player-name');
-    bottomNameElement.textContent = myInfo ? myInfo.name + ' (我)' : '我';
+    if (spectating) {
+        bottomNameElement.textContent = players.length > 0 ? players[0].name : '等待加入';
+    } else {
+        bottomNameElement.textContent = myInfo ? myInfo.name + ' (我)' : '我';
+    }
     
-    // 获取其他玩家（不包括自己）
-    const otherPlayers = players.filter(p => p.id !== playerID);
+    // 获取其他玩家（不包括下方的玩家）
+    const otherPlayers = players.filter(p => p.id !== bottomID);
     console.log("其他玩家:", otherPlayers);
     
     // 根据其他玩家数量分配位置
@@ -438,8 +692,8 @@ function updateTablePlayers() {
     if (gameState === 'playing') {
         const currentPlayer = players.find(p => p.isCurrentTurn);
         if (currentPlayer) {
-            if (currentPlayer.id === playerID) {
-                // 是我的回合
+            if (currentPlayer.id === bottomID) {
+                // 是下方玩家的回合
                 bottomElement.classList.add('current-turn');
             } else {
                 // 是其他玩家的回合
@@ -506,28 +760,140 @@ function startGame() {
     }
 }
 
-// 出牌
+// 出牌，服务端确认（收到tile_played）后再从手牌中移除
 function playTile() {
     if (isMyTurn && selectedTileIndex !== -1) {
         const tile = myTiles[selectedTileIndex];
         sendMessage('play_tile', { tile: tile });
         
-        // 从手牌中移除
-        myTiles.splice(selectedTileIndex, 1);
-        selectedTileIndex = -1;
-        renderMyTiles();
-        
         // 禁用出牌按钮
         document.getElementById('playTileBtn').disabled = true;
     }
 }
 
-// 执行操作（吃碰杠胡）
-function doAction(action, tiles) {
+// 执行操作（碰杠胡过）
+function doAction(action) {
+    let tiles = [];
+    if (action === 'gang') {
+        // 响应别人出的牌时杠那张牌，自己回合杠手里能杠的第一张
+        tiles = claimTile ? [claimTile] : findGangTiles().slice(0, 1);
+    }
     sendMessage('action', {
         action: action,
         tiles: tiles
     });
+    showOptions([]);
+}
+
+// 自己回合可以暗杠或补杠的牌
+function findGangTiles() {
+    const result = [];
+    myTiles.forEach(tile => {
+        if (result.includes(tile) || tile[1] === myMissing) {
+            return;
+        }
+        const count = myTiles.filter(t => t === tile).length;
+        const pengd = myMelds.some(m => m.kind === 'peng' && m.tile === tile);
+        if (count === 4 || pengd) {
+            result.push(tile);
+        }
+    });
+    return result;
+}
+
+// 显示可以执行的动作按钮
+function showOptions(options) {
+    ['peng', 'gang', 'hu', 'pass'].forEach(action => {
+        const btn = document.getElementById(`${action}Btn`);
+        btn.style.display = options.includes(action) ? 'inline-block' : 'none';
+    });
+}
+
+// 定缺
+function declareMissing(suit) {
+    sendMessage('dingque', { suit: suit });
+    myMissing = suit;
+    document.getElementById('dingqueButtons').style.display = 'none';
+    addChatMessage('系统', `你定缺了${getSuitText(suit)}，等待其他玩家定缺`);
+}
+
+// 渲染自己碰杠的牌
+function renderMyMelds() {
+    const meldsElement = document.getElementById('myMelds');
+    meldsElement.innerHTML = '';
+    myMelds.forEach(meld => {
+        const meldElement = document.createElement('div');
+        meldElement.className = 'meld';
+        const count = meld.kind === 'peng' ? 3 : 4;
+        for (let i = 0; i < count; i++) {
+            const tileElement = document.createElement('div');
+            tileElement.className = 'tile';
+            tileElement.textContent = getTileText(meld.tile);
+            meldElement.appendChild(tileElement);
+        }
+        meldsElement.appendChild(meldElement);
+    });
+}
+
+// 从手牌中移除n张牌
+function removeMyTiles(tile, count) {
+    for (let i = 0; i < count; i++) {
+        const index = myTiles.indexOf(tile);
+        if (index !== -1) {
+            myTiles.splice(index, 1);
+        }
+    }
+    selectedTileIndex = -1;
+    renderMyTiles();
+}
+
+// 处理定缺结果
+function handleDingque(data) {
+    const texts = [];
+    for (const id in data.suits) {
+        const playerName = players.find(p => p.id === id)?.name || '玩家';
+        texts.push(`${playerName} 缺${getSuitText(data.suits[id])}`);
+    }
+    addChatMessage('系统', '定缺完成：' + texts.join('，'));
+}
+
+// 处理碰杠胡的提示，只有能操作的玩家会收到
+function handleClaimWindow(data) {
+    startCountdown(data.seconds, 0);
+    claimTile = data.tile;
+    showOptions(data.options);
+    addChatMessage('系统', `可以对 ${getTileText(data.tile)} 选择：${data.options.map(getActionText).join('、')}`);
+}
+
+// 处理碰杠
+function handleMeld(data) {
+    claimTile = '';
+    showOptions([]);
+    
+    // 被碰、明杠的牌从弃牌区拿走
+    if (data.from) {
+        const discardedTiles = document.getElementById('discardedTiles');
+        if (discardedTiles.lastChild) {
+            discardedTiles.removeChild(discardedTiles.lastChild);
+        }
+    }
+    
+    if (data.playerID === playerID) {
+        const removed = { peng: 2, ming_gang: 3, an_gang: 4, bu_gang: 1 }[data.kind];
+        removeMyTiles(data.tile, removed);
+        if (data.kind === 'bu_gang') {
+            const meld = myMelds.find(m => m.kind === 'peng' && m.tile === data.tile);
+            if (meld) {
+                meld.kind = 'bu_gang';
+            }
+        } else {
+            myMelds.push({ kind: data.kind, tile: data.tile, from: data.from });
+        }
+        renderMyMelds();
+    }
+    
+    const playerName = players.find(p => p.id === data.playerID)?.name || '玩家';
+    addChatMessage('系统', `${playerName} ${getActionText(data.kind)} ${data.tile ? getTileText(data.tile) : ''}`);
 }
 
 // 处理游戏开始
@@ -535,19 +901,33 @@ function handleGameStarted(data) {
     gameState = 'playing';
     document.getElementById('gameStatus').textContent = '游戏进行中';
     document.getElementById('startGameBtn').style.display = 'none';
+    updateAddBotButton();
+    updateAutoPlayButton();
+    startCountdown(data.seconds, 0);
     
     // 显示操作按钮
     document.getElementById('actionButtons').style.display = 'flex';
+    showOptions(data.options || []);
     
     // 设置出牌按钮事件
     document.getElementById('playTileBtn').onclick = playTile;
     
     // 设置操作按钮事件
-    document.getElementById('chiBtn').onclick = function() { doAction('chi', []); };
-    document.getElementById('pengBtn').onclick = function() { doAction('peng', []); };
-    document.getElementById('gangBtn').onclick = function() { doAction('gang', []); };
-    document.getElementById('huBtn').onclick = function() { doAction('hu', []); };
-    document.getElementById('passBtn').onclick = function() { doAction('pass', []); };
+    document.getElementById('pengBtn').onclick = function() { doAction('peng'); };
+    document.getElementById('gangBtn').onclick = function() { doAction('gang'); };
+    document.getElementById('huBtn').onclick = function() { doAction('hu'); };
+    document.getElementById('passBtn').onclick = function() { doAction('pass'); };
+    
+    // 定缺阶段：还没定缺时显示定缺按钮
+    myMelds = data.melds || [];
+    myMissing = data.missing || '';
+    claimTile = data.claimTile || '';
+    renderMyMelds();
+    const dingqueButtons = document.getElementById('dingqueButtons');
+    dingqueButtons.style.display = data.phase === 'dingque' && !myMissing ? 'flex' : 'none';
+    dingqueButtons.querySelectorAll('button').forEach(btn => {
+        btn.onclick = function() { declareMissing(btn.dataset.suit); };
+    });
     
     // 更新我的手牌
     if (data.tiles) {
@@ -555,10 +935,12 @@ function handleGameStarted(data) {
         renderMyTiles();
     }
     
-    // 更新当前玩家
-    if (data.currentPlayerID) {
+    // 更新当前玩家，定缺阶段还不能出牌
+    if (data.currentPlayerID && data.phase !== 'dingque') {
         handleTurnChanged({
-            playerID: data.currentPlayerID
+            playerID: data.currentPlayerID,
+            options: data.phase === 'discard' ? data.options : [],
+            seconds: data.seconds
         });
     }
     
@@ -569,7 +951,44 @@ function handleGameStarted(data) {
     updateTablePlayers();
     
     // 添加系统消息
-    addChatMessage('系统', '游戏开始了！');
+    if (data.phase === 'dingque' && !myMissing) {
+        addChatMessage('系统', '游戏开始了！请先定缺');
+    } else {
+        addChatMessage('系统', '游戏开始了！');
+    }
+}
+
+// 处理对局快照（刷新页面或断线重连后恢复对局）
+function handleGameSnapshot(data) {
+    players = data.players || [];
+    myInfo = players.find(p => p.id === playerID);
+    autoPlaying = !!(myInfo && myInfo.autoPlay);
+    
+    // 复用游戏开始的界面初始化逻辑
+    const me = myInfo || {};
+    handleGameStarted({
+        tiles: data.tiles,
+        currentPlayerID: data.currentPlayerID,
+        phase: data.phase,
+        melds: me.melds,
+        missing: me.missing,
+        options: data.options,
+        claimTile: data.phase === 'claim' && data.options ? data.lastPlayedTile : '',
+        seconds: data.seconds
+    });
+    
+    // 恢复弃牌区
+    const discardedTiles = document.getElementById('discardedTiles');
+    discardedTiles.innerHTML = '';
+    (data.discardedTiles || []).forEach(tile => {
+        const tileElement = document.createElement('div');
+        tileElement.className = 'tile';
+        tileElement.dataset.tile = tile;
+        tileElement.textContent = getTileText(tile);
+        discardedTiles.appendChild(tileElement);
+    });
+    
+    addChatMessage('系统', '已恢复对局');
 }
 
 // 处理出牌
@@ -585,6 +1004,11 @@ function handleTilePlayed(data) {
     tileElement.textContent = getTileText(tile);
     discardedTiles.appendChild(tileElement);
     
+    // 自己打出的牌从手牌中移除
+    if (data.playerID === myInfo?.id) {
+        removeMyTiles(tile, 1);
+    }
+    
     // 添加系统消息
     const playerName = players.find(p => p.id === playerID)?.name || '玩家';
     addChatMessage('系统', `${playerName} 打出了 ${getTileText(tile)}`);
@@ -592,6 +1016,10 @@ function handleTilePlayed(data) {
 
 // 处理新抽到的牌
 function handleNewTile(data) {
+    // 其他玩家摸牌时看不到牌面
+    if (data.playerID !== playerID) {
+        return;
+    }
     const tile = data.tile;
     
     // 添加到手牌
@@ -605,6 +1033,9 @@ function handleNewTile(data) {
 // 处理轮到谁出牌
 function handleTurnChanged(data) {
     const currentPlayerID = data.playerID;
+    claimTile = '';
+    showOptions(data.options || []);
+    startCountdown(data.seconds, data.timeBank);
     
     // 更新玩家的当前回合标记
     players.forEach(p => {
@@ -629,25 +1060,11 @@ function handleTurnChanged(data) {
     }
 }
 
-// 处理需要玩家操作（吃碰杠胡）
-function handleActionRequired(data) {
-    const actions = data.actions;
-    
-    // 显示相应的操作按钮
-    document.getElementById('chiBtn').style.display = actions.includes('chi') ? 'block' : 'none';
-    document.getElementById('pengBtn').style.display = actions.includes('peng') ? 'block' : 'none';
-    document.getElementById('gangBtn').style.display = actions.includes('gang') ? 'block' : 'none';
-    document.getElementById('huBtn').style.display = actions.includes('hu') ? 'block' : 'none';
-    document.getElementById('passBtn').style.display = 'block';
-    
-    // 添加系统消息
-    addChatMessage('系统', '请选择操作');
-}
-
 // 处理游戏结束
 function handleGameOver(data) {
     gameState = 'finished';
     document.getElementById('gameStatus').textContent = getGameStateText(gameState);
+    startCountdown(0, 0);
     
     // 隐藏操作按钮
     document.getElementById('actionButtons').style.display = 'none';
@@ -659,17 +1076,24 @@ function handleGameOver(data) {
     let resultMessage = '游戏结束！\n';
     if (winner) {
         const winnerName = players.find(p => p.id === winner.id)?.name || '玩家';
-        resultMessage += `${winnerName} 胡牌获胜！\n`;
+        const how = data.selfDrawn ? '自摸' : '胡';
+        resultMessage += `${winnerName} ${how} ${getTileText(data.winningTile)}，${data.fan}番（${(data.patterns || []).join('、')}）\n`;
+    } else {
+        resultMessage += '流局\n';
     }
     
     resultMessage += '最终得分：\n';
     for (const playerID in scores) {
         const playerName = players.find(p => p.id === playerID)?.name || '玩家';
-        resultMessage += `${playerName}: ${scores[playerID]}分\n`;
+        const delta = (data.deltas || {})[playerID] || 0;
+        resultMessage += `${playerName}: ${scores[playerID]}分（${delta >= 0 ? '+' : ''}${delta}）\n`;
     }
     
     // 添加系统消息
     addChatMessage('系统', resultMessage);
+    if (data.replayID) {
+        addChatMessage('系统', `<a href="/replay/${data.replayID}" target="_blank">查看本局回放</a>`);
+    }
     
     // 如果是房主，显示开始新游戏按钮
     const isOwner = players.find(p => p.id === playerID)?.isOwner;
@@ -677,6 +1101,55 @@ function handleGameOver(data) {
         document.getElementById('startGameBtn').style.display = 'block';
         document.getElementById('startGameBtn').textContent = '开始新游戏';
     }
+    updateAddBotButton();
+    updateAutoPlayButton();
+}
+
+// 显示观战人数
+function updateSpectatorCount(count) {
+    document.getElementById('spectatorCount').textContent = count ? `观战 ${count} 人` : '';
+}
+
+// 教练视角：显示延迟公开的所有人手牌和定缺
+function handleCoachView(data) {
+    document.getElementById('coachPanel').style.display = 'block';
+    document.getElementById('coachDelay').textContent = `${data.delay} 秒前的手牌`;
+    const hands = document.getElementById('coachHands');
+    hands.innerHTML = '';
+    (data.hands || []).forEach(hand => {
+        const player = players.find(p => p.id === hand.playerID);
+        const missing = (data.missing || {})[hand.playerID];
+        const element = document.createElement('div');
+        element.className = 'coach-hand';
+        element.innerHTML = `<strong>${player ? player.name : '玩家'}</strong>${missing ? ` 缺${getSuitText(missing)}` : ''}${hand.playerID === data.currentPlayerID ? ' ◀' : ''}`;
+        const tiles = document.createElement('div');
+        tiles.className = 'tile-count';
+        (hand.tiles || []).forEach(tile => tiles.appendChild(createTileElement(tile)));
+        element.appendChild(tiles);
+        hands.appendChild(element);
+    });
+}
+
+// 房主在开局前、房间没满时可以添加机器人
+function updateAddBotButton() {
+    const isOwner = players.find(p => p.id === playerID)?.isOwner;
+    const visible = isOwner && gameState !== 'playing' && players.length < 4;
+    document.getElementById('addBotBtn').style.display = visible ? 'block' : 'none';
+    document.getElementById('botLevel').style.display = visible ? 'block' : 'none';
+}
+
+// 获取机器人难度文本
+function getBotLevelText(level) {
+    switch (level) {
+        case 'easy':
+            return '简单';
+        case 'hard':
+            return '困难';
+        case 'expert':
+            return '专家';
+        default:
+            return '普通';
+    }
 }
 
 // 获取游戏状态文本
@@ -693,6 +1166,19 @@ function getGameStateText(state) {
     }
 }
 
+// 获取花色的显示文本
+function getSuitText(suit) {
+    return { t: '条', p: '筒', w: '万' }[suit] || suit;
+}
+
+// 获取动作的显示文本
+function getActionText(action) {
+    return {
+        peng: '碰', gang: '杠', hu: '胡', pass: '过',
+        ming_gang: '明杠', an_gang: '暗杠', bu_gang: '补杠'
+    }[action] || action;
+}
+
 // 获取麻将牌的显示文本
 function getTileText(tile) {
     // 简化版，实际应该根据牌的编码显示对应的文字或符号
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"goMahjong/model"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bbolt中的桶，值都是JSON
+var (
+	bucketRooms   = []byte("rooms")
+	bucketPlayers = []byte("players")
+	bucketResults = []byte("results") // 键为 房间ID/结束时间/回放ID，按前缀扫描一个房间的结果
+	bucketReplays = []byte("replays")
+	bucketMeta    = []byte("meta")
+)
+
+// Bolt 保存在本地bbolt数据库文件中的存储
+type Bolt struct {
+	db *bolt.DB
+}
+
+// OpenBolt 打开（不存在时创建）数据库文件
+// 同一个文件只能被一个进程打开，超时说明有另一个服务实例在使用
+func OpenBolt(path string) (*Bolt, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketRooms, bucketPlayers, bucketResults, bucketReplays, bucketMeta} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Bolt{db: db}, nil
+}
+
+// put 把值编码成JSON写入桶
+func (b *Bolt) put(bucket []byte, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), data)
+	})
+}
+
+// get 读取桶中的值，不存在时返回 ErrNotFound
+func (b *Bolt) get(bucket []byte, key string, value interface{}) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get([]byte(key))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, value)
+	})
+}
+
+func (b *Bolt) delete(bucket []byte, key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete([]byte(key))
+	})
+}
+
+// SaveRoom 保存房间记录
+func (b *Bolt) SaveRoom(room RoomRecord) error {
+	return b.put(bucketRooms, room.ID, room)
+}
+
+// GetRoom 读取房间记录
+func (b *Bolt) GetRoom(id string) (RoomRecord, error) {
+	var room RoomRecord
+	err := b.get(bucketRooms, id, &room)
+	return room, err
+}
+
+// DeleteRoom 删除房间记录，对局结果和回放保留
+func (b *Bolt) DeleteRoom(id string) error {
+	return b.delete(bucketRooms, id)
+}
+
+// ListRooms 按房间ID顺序返回所有房间记录
+func (b *Bolt) ListRooms() ([]RoomRecord, error) {
+	rooms := make([]RoomRecord, 0)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketRooms).ForEach(func(_, data []byte) error {
+			var room RoomRecord
+			if err := json.Unmarshal(data, &room); err != nil {
+				return err
+			}
+			rooms = append(rooms, room)
+			return nil
+		})
+	})
+	return rooms, err
+}
+
+// SavePlayer 保存玩家记录
+func (b *Bolt) SavePlayer(player PlayerRecord) error {
+	return b.put(bucketPlayers, player.ID, player)
+}
+
+// GetPlayer 读取玩家记录
+func (b *Bolt) GetPlayer(id string) (PlayerRecord, error) {
+	var player PlayerRecord
+	err := b.get(bucketPlayers, id, &player)
+	return player, err
+}
+
+// DeletePlayer 删除玩家记录
+func (b *Bolt) DeletePlayer(id string) error {
+	return b.delete(bucketPlayers, id)
+}
+
+// SaveResult 保存对局结果
+func (b *Bolt) SaveResult(result MatchResult) error {
+	key := result.RoomID + "/" + result.FinishedAt.UTC().Format("20060102150405.000000000") + "/" + result.ReplayID
+	return b.put(bucketResults, key, result)
+}
+
+// ListResults 按结束时间顺序返回房间的对局结果
+func (b *Bolt) ListResults(roomID string) ([]MatchResult, error) {
+	results := make([]MatchResult, 0)
+	prefix := []byte(roomID + "/")
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketResults).Cursor()
+		for k, data := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, data = c.Next() {
+			var result MatchResult
+			if err := json.Unmarshal(data, &result); err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	return results, err
+}
+
+// SaveReplay 保存回放
+func (b *Bolt) SaveReplay(replay *model.Replay) error {
+	return b.put(bucketReplays, replay.ID, replay)
+}
+
+// GetReplay 读取回放
+func (b *Bolt) GetReplay(id string) (*model.Replay, error) {
+	replay := &model.Replay{}
+	if err := b.get(bucketReplays, id, replay); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
+// GetMeta 读取配置
+func (b *Bolt) GetMeta(key string) (string, error) {
+	var value string
+	err := b.get(bucketMeta, key, &value)
+	return value, err
+}
+
+// SaveMeta 保存配置
+func (b *Bolt) SaveMeta(key, value string) error {
+	return b.put(bucketMeta, key, value)
+}
+
+// Close 关闭数据库文件
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
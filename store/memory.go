@@ -0,0 +1,156 @@
+package store
+
+import (
+	"goMahjong/model"
+	"sort"
+	"sync"
+)
+
+// Memory 内存中的存储，用于开发和测试
+type Memory struct {
+	mutex   sync.RWMutex
+	rooms   map[string]RoomRecord
+	players map[string]PlayerRecord
+	results map[string][]MatchResult // 房间ID -> 对局结果
+	replays map[string]*model.Replay
+	meta    map[string]string
+}
+
+// NewMemory 创建内存存储
+func NewMemory() *Memory {
+	return &Memory{
+		rooms:   make(map[string]RoomRecord),
+		players: make(map[string]PlayerRecord),
+		results: make(map[string][]MatchResult),
+		replays: make(map[string]*model.Replay),
+		meta:    make(map[string]string),
+	}
+}
+
+// SaveRoom 保存房间记录
+func (m *Memory) SaveRoom(room RoomRecord) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	room.PlayerIDs = append([]string(nil), room.PlayerIDs...)
+	room.Hand = append([]model.Event(nil), room.Hand...)
+	m.rooms[room.ID] = room
+	return nil
+}
+
+// GetRoom 读取房间记录
+func (m *Memory) GetRoom(id string) (RoomRecord, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	room, ok := m.rooms[id]
+	if !ok {
+		return RoomRecord{}, ErrNotFound
+	}
+	return room, nil
+}
+
+// DeleteRoom 删除房间记录，对局结果和回放保留
+func (m *Memory) DeleteRoom(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.rooms, id)
+	return nil
+}
+
+// ListRooms 按房间ID顺序返回所有房间记录
+func (m *Memory) ListRooms() ([]RoomRecord, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	rooms := make([]RoomRecord, 0, len(m.rooms))
+	for _, room := range m.rooms {
+		rooms = append(rooms, room)
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].ID < rooms[j].ID })
+	return rooms, nil
+}
+
+// SavePlayer 保存玩家记录
+func (m *Memory) SavePlayer(player PlayerRecord) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.players[player.ID] = player
+	return nil
+}
+
+// GetPlayer 读取玩家记录
+func (m *Memory) GetPlayer(id string) (PlayerRecord, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	player, ok := m.players[id]
+	if !ok {
+		return PlayerRecord{}, ErrNotFound
+	}
+	return player, nil
+}
+
+// DeletePlayer 删除玩家记录
+func (m *Memory) DeletePlayer(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.players, id)
+	return nil
+}
+
+// SaveResult 保存对局结果
+func (m *Memory) SaveResult(result MatchResult) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	results := append(m.results[result.RoomID], result)
+	sort.SliceStable(results, func(i, j int) bool { return results[i].FinishedAt.Before(results[j].FinishedAt) })
+	m.results[result.RoomID] = results
+	return nil
+}
+
+// ListResults 按结束时间顺序返回房间的对局结果
+func (m *Memory) ListResults(roomID string) ([]MatchResult, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return append([]MatchResult{}, m.results[roomID]...), nil
+}
+
+// SaveReplay 保存回放
+func (m *Memory) SaveReplay(replay *model.Replay) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.replays[replay.ID] = replay
+	return nil
+}
+
+// GetReplay 读取回放
+func (m *Memory) GetReplay(id string) (*model.Replay, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	replay, ok := m.replays[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return replay, nil
+}
+
+// GetMeta 读取配置
+func (m *Memory) GetMeta(key string) (string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	value, ok := m.meta[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// SaveMeta 保存配置
+func (m *Memory) SaveMeta(key, value string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.meta[key] = value
+	return nil
+}
+
+// Close 内存存储不需要关闭
+func (m *Memory) Close() error {
+	return nil
+}
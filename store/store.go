@@ -0,0 +1,127 @@
+// Package store 持久化房间、玩家、对局结果和回放
+//
+// 房间对象本身带着连接、锁和计时器，只能留在内存里；这里保存的是可以序列化的记录，
+// 由 service.GameManager 在房间变化时写入。
+package store
+
+import (
+	"errors"
+	"goMahjong/model"
+	"time"
+)
+
+// ErrNotFound 记录不存在
+var ErrNotFound = errors.New("record not found")
+
+// 存储驱动
+const (
+	DriverMemory = "memory" // 只保存在内存中，重启后丢失
+	DriverBolt   = "bolt"   // 保存在本地的bbolt数据库文件中
+)
+
+// RoomRecord 房间记录
+type RoomRecord struct {
+	ID           string          `json:"id"`
+	PasswordHash []byte          `json:"passwordHash,omitempty"`
+	OwnerID      string          `json:"ownerID"`
+	CreatorIP    string          `json:"creatorIP,omitempty"` // 创建者的IP，恢复后继续计入每个IP的房间数
+	PlayerIDs    []string        `json:"playerIDs"`           // 按座位顺序
+	GameState    model.GameState `json:"gameState"`
+	Seq          uint64          `json:"seq"`            // 保存时房间的消息序号
+	Hand         []model.Event   `json:"hand,omitempty"` // 最近一局的事件，重放即可恢复牌墙、手牌、弃牌、轮次和分数
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+// PlayerRecord 玩家记录，玩家只属于一个房间
+type PlayerRecord struct {
+	ID       string `json:"id"`
+	RoomID   string `json:"roomID"`
+	Name     string `json:"name"`
+	Score    int    `json:"score"`
+	Bot      bool   `json:"bot,omitempty"`      // 机器人，恢复房间时重新接管座位
+	BotLevel string `json:"botLevel,omitempty"` // 机器人的难度
+}
+
+// MatchResult 一局的结果
+type MatchResult struct {
+	ReplayID   string         `json:"replayID"`
+	RoomID     string         `json:"roomID"`
+	WinnerID   string         `json:"winnerID,omitempty"` // 流局时为空
+	Scores     map[string]int `json:"scores"`             // 玩家ID -> 结算后的分数
+	Deltas     map[string]int `json:"deltas"`             // 玩家ID -> 本局输赢
+	FinishedAt time.Time      `json:"finishedAt"`
+}
+
+// RoomStore 房间记录的存储
+type RoomStore interface {
+	SaveRoom(room RoomRecord) error
+	GetRoom(id string) (RoomRecord, error)
+	DeleteRoom(id string) error
+	ListRooms() ([]RoomRecord, error)
+}
+
+// PlayerStore 玩家记录的存储
+type PlayerStore interface {
+	SavePlayer(player PlayerRecord) error
+	GetPlayer(id string) (PlayerRecord, error)
+	DeletePlayer(id string) error
+}
+
+// ResultStore 对局结果的存储
+type ResultStore interface {
+	SaveResult(result MatchResult) error
+	// ListResults 按结束时间顺序返回房间的对局结果
+	ListResults(roomID string) ([]MatchResult, error)
+}
+
+// ReplayStore 回放的存储
+type ReplayStore interface {
+	SaveReplay(replay *model.Replay) error
+	GetReplay(id string) (*model.Replay, error)
+}
+
+// MetaStore 服务自身的少量配置，如自动生成的会话密钥
+type MetaStore interface {
+	GetMeta(key string) (string, error)
+	SaveMeta(key, value string) error
+}
+
+// Store 全部存储，GameManager 通过它读写持久化的数据
+type Store interface {
+	RoomStore
+	PlayerStore
+	ResultStore
+	ReplayStore
+	MetaStore
+	Close() error
+}
+
+// Open 按驱动名打开存储，path 是bbolt数据库文件的路径
+func Open(driver, path string) (Store, error) {
+	switch driver {
+	case DriverMemory:
+		return NewMemory(), nil
+	case DriverBolt:
+		return OpenBolt(path)
+	}
+	return nil, errors.New("未知的存储驱动: " + driver)
+}
+
+// NewMatchResult 从回放中整理出对局结果
+func NewMatchResult(replay *model.Replay) MatchResult {
+	result := MatchResult{
+		ReplayID:   replay.ID,
+		RoomID:     replay.RoomID,
+		Scores:     replay.FinalScores,
+		FinishedAt: replay.CreatedAt,
+	}
+	for _, e := range replay.Events {
+		switch e.Type {
+		case model.EventWin:
+			result.WinnerID = e.PlayerID
+		case model.EventSettlement:
+			result.Deltas = e.Settlement.Deltas
+		}
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"goMahjong/model"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 两种实现跑同一组测试
+func eachStore(t *testing.T, fn func(t *testing.T, s Store)) {
+	t.Run(DriverMemory, func(t *testing.T) {
+		fn(t, NewMemory())
+	})
+	t.Run(DriverBolt, func(t *testing.T) {
+		s, err := OpenBolt(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		fn(t, s)
+	})
+}
+
+func TestRoomsAndPlayers(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		_, err := s.GetRoom("abc123")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		room := RoomRecord{ID: "abc123", OwnerID: "p1", PlayerIDs: []string{"p1", "p2"}, GameState: model.GameStateWaiting}
+		require.NoError(t, s.SaveRoom(room))
+		require.NoError(t, s.SaveRoom(RoomRecord{ID: "000000"}))
+		require.NoError(t, s.SavePlayer(PlayerRecord{ID: "p1", RoomID: "abc123", Name: "东", Score: 3}))
+
+		got, err := s.GetRoom("abc123")
+		require.NoError(t, err)
+		assert.Equal(t, room.PlayerIDs, got.PlayerIDs)
+		player, err := s.GetPlayer("p1")
+		require.NoError(t, err)
+		assert.Equal(t, 3, player.Score)
+
+		rooms, err := s.ListRooms()
+		require.NoError(t, err)
+		require.Len(t, rooms, 2)
+		assert.Equal(t, "000000", rooms[0].ID)
+
+		require.NoError(t, s.DeleteRoom("abc123"))
+		require.NoError(t, s.DeletePlayer("p1"))
+		_, err = s.GetRoom("abc123")
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = s.GetPlayer("p1")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestResultsAndReplays(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		// 后结束的先保存，读取时仍按结束时间排序
+		require.NoError(t, s.SaveResult(MatchResult{ReplayID: "r2", RoomID: "abc123", FinishedAt: start.Add(time.Minute)}))
+		require.NoError(t, s.SaveResult(MatchResult{ReplayID: "r1", RoomID: "abc123", FinishedAt: start}))
+		require.NoError(t, s.SaveResult(MatchResult{ReplayID: "r3", RoomID: "abc1234", FinishedAt: start}))
+
+		results, err := s.ListResults("abc123")
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "r1", results[0].ReplayID)
+		assert.Equal(t, "r2", results[1].ReplayID)
+
+		replay := &model.Replay{Version: model.ReplayVersion, ID: "r1", RoomID: "abc123", FinalScores: map[string]int{"p1": 2}}
+		require.NoError(t, s.SaveReplay(replay))
+		got, err := s.GetReplay("r1")
+		require.NoError(t, err)
+		assert.Equal(t, replay.FinalScores, got.FinalScores)
+		_, err = s.GetReplay("r9")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestMeta(t *testing.T) {
+	eachStore(t, func(t *testing.T, s Store) {
+		_, err := s.GetMeta("sessionSecret")
+		assert.ErrorIs(t, err, ErrNotFound)
+		require.NoError(t, s.SaveMeta("sessionSecret", "abc"))
+		value, err := s.GetMeta("sessionSecret")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", value)
+	})
+}
+
+func TestBoltPersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := Open(DriverBolt, path)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveRoom(RoomRecord{ID: "abc123"}))
+	require.NoError(t, s.Close())
+
+	s, err = Open(DriverBolt, path)
+	require.NoError(t, err)
+	defer s.Close()
+	_, err = s.GetRoom("abc123")
+	assert.NoError(t, err)
+
+	_, err = Open("mysql", path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package transport
+
+import (
+	"goMahjong/model"
+	"time"
+)
+
+// Client 玩家消息的接收方，WebSocket连接和机器人都实现这个接口
+// Send 在持有房间锁时调用，不能阻塞
+type Client interface {
+	Send(message model.Message) error
+	Close()
+}
+
+// Clients 一个房间的客户端登记表，按玩家ID登记玩家的连接（机器人玩家登记的是机器人本身）、
+// 托管时代替玩家操作的机器人和掉线后保留座位的计时器
+// 引擎只按玩家ID产生消息，由这里交给客户端；和房间的其他状态一样，调用方需持有房间锁
+type Clients struct {
+	conns      map[string]Client
+	autopilots map[string]Client
+	holds      map[string]*time.Timer
+}
+
+// NewClients 创建空的登记表
+func NewClients() *Clients {
+	return &Clients{
+		conns:      make(map[string]Client),
+		autopilots: make(map[string]Client),
+		holds:      make(map[string]*time.Timer),
+	}
+}
+
+// For 返回房间的客户端登记表，房间的 Transport 不是登记表时返回nil
+func For(room *model.Room) *Clients {
+	clients, _ := room.Transport.(*Clients)
+	return clients
+}
+
+// Deliver 实现 model.Transport，消息进入客户端的发送队列后立即返回；托管时同时交给托管的机器人
+func (c *Clients) Deliver(playerID string, message model.Message) {
+	if autopilot := c.autopilots[playerID]; autopilot != nil {
+		autopilot.Send(message)
+	}
+	if conn := c.conns[playerID]; conn != nil {
+		conn.Send(message)
+	}
+}
+
+// Attach 登记玩家的连接，同一玩家的旧连接直接关闭
+func (c *Clients) Attach(playerID string, conn Client) {
+	if old := c.conns[playerID]; old != nil && old != conn {
+		old.Close()
+	}
+	c.conns[playerID] = conn
+}
+
+// Conn 返回玩家当前登记的连接，没有时返回nil
+func (c *Clients) Conn(playerID string) Client {
+	return c.conns[playerID]
+}
+
+// Detach 取消登记玩家的连接并返回它，不关闭连接
+// 先取消登记再关闭，读协程随后退出时发现连接已经不是玩家的连接，不会再按掉线处理
+func (c *Clients) Detach(playerID string) Client {
+	conn := c.conns[playerID]
+	delete(c.conns, playerID)
+	return conn
+}
+
+// StartAutoPlay 由 autopilot 代替玩家操作，替换并停止之前托管的机器人
+func (c *Clients) StartAutoPlay(playerID string, autopilot Client) {
+	c.StopAutoPlay(playerID)
+	c.autopilots[playerID] = autopilot
+}
+
+// StopAutoPlay 停止托管的机器人
+func (c *Clients) StopAutoPlay(playerID string) {
+	if autopilot := c.autopilots[playerID]; autopilot != nil {
+		autopilot.Close()
+		delete(c.autopilots, playerID)
+	}
+}
+
+// Hold 玩家掉线后开始计时，超时仍未重连则执行 onExpire
+func (c *Clients) Hold(playerID string, d time.Duration, onExpire func()) {
+	c.Release(playerID)
+	c.holds[playerID] = time.AfterFunc(d, onExpire)
+}
+
+// Holding 玩家是否处于掉线保留座位阶段
+func (c *Clients) Holding(playerID string) bool {
+	return c.holds[playerID] != nil
+}
+
+// Release 玩家重连或离开后取消计时
+func (c *Clients) Release(playerID string) {
+	if timer := c.holds[playerID]; timer != nil {
+		timer.Stop()
+		delete(c.holds, playerID)
+	}
+}
+
+// Serves 客户端是否仍然登记为玩家的连接或托管的机器人
+func (c *Clients) Serves(playerID string, client Client) bool {
+	return client != nil && (c.conns[playerID] == client || c.autopilots[playerID] == client)
+}
+
+// Remove 玩家离开房间：取消计时、停止托管的机器人并关闭连接
+func (c *Clients) Remove(playerID string) {
+	c.Release(playerID)
+	c.StopAutoPlay(playerID)
+	if conn := c.Detach(playerID); conn != nil {
+		conn.Close()
+	}
+}
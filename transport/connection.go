@@ -0,0 +1,175 @@
+// Package transport 传输层：把对局引擎产生的消息投递给玩家的WebSocket连接和机器人，
+// 引擎本身只产生按收件人生成的消息，不知道消息怎样送达
+package transport
+
+import (
+	"errors"
+	"goMahjong/config"
+	"goMahjong/model"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+)
+
+var (
+	// ErrConnectionClosed 连接已关闭
+	ErrConnectionClosed = errors.New("connection closed")
+	// ErrSendQueueFull 发送队列已满，客户端消费过慢
+	ErrSendQueueFull = errors.New("send queue full")
+)
+
+// Connection 封装一个WebSocket连接
+// gorilla/websocket 只允许一个写协程，所以所有消息先进入发送队列，再由 writePump 统一写出
+type Connection struct {
+	ws        *websocket.Conn
+	version   int                // 协商好的协议版本
+	codec     model.Codec        // 协商好的编码格式
+	send      chan model.Message // 发送队列
+	closing   chan []byte        // 服务端主动关闭时要发送的关闭帧
+	done      chan struct{}      // 连接关闭信号
+	closeOnce sync.Once
+}
+
+// NewConnection 包装WebSocket连接并启动写协程
+// 读超时由心跳维持：服务端定期发送ping，收到pong或任何消息都会延长读超时
+func NewConnection(ws *websocket.Conn, version int, codec model.Codec) *Connection {
+	c := &Connection{
+		ws:      ws,
+		version: version,
+		codec:   codec,
+		send:    make(chan model.Message, viper.GetInt("websocket.sendQueueSize")),
+		closing: make(chan []byte, 1),
+		done:    make(chan struct{}),
+	}
+
+	pongWait := viper.GetDuration("websocket.pongWait")
+	ws.SetReadLimit(viper.GetInt64("websocket.maxMessageSize"))
+	ws.SetReadDeadline(time.Now().Add(pongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	go c.writePump()
+	return c
+}
+
+// Send 将消息放入发送队列，不会阻塞调用方
+// 队列已满说明客户端落后太多，直接断开它，避免拖慢整个房间的广播
+func (c *Connection) Send(message model.Message) error {
+	select {
+	case <-c.done:
+		return ErrConnectionClosed
+	default:
+	}
+
+	message.Version = c.version
+	select {
+	case c.send <- message:
+		return nil
+	default:
+		config.GetZapLogger().Warn("客户端发送队列已满，断开连接: " + c.ws.RemoteAddr().String())
+		c.Close()
+		return ErrSendQueueFull
+	}
+}
+
+// Version 返回连接协商好的协议版本
+func (c *Connection) Version() int {
+	return c.version
+}
+
+// Codec 返回连接使用的编码
+func (c *Connection) Codec() model.Codec {
+	return c.codec
+}
+
+// ReadMessage 读取一条客户端消息，只能由读协程调用
+func (c *Connection) ReadMessage() ([]byte, error) {
+	_, msg, err := c.ws.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	c.ws.SetReadDeadline(time.Now().Add(viper.GetDuration("websocket.pongWait")))
+	return msg, nil
+}
+
+// IsTimeout 判断读错误是否由心跳超时引起
+func IsTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// Close 关闭连接，可重复调用
+func (c *Connection) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.ws.Close()
+	})
+}
+
+// Shutdown 发完队列中已有的消息后发送关闭帧再关闭连接，用于服务端主动断开
+// code 使用 websocket.CloseGoingAway 等标准关闭码，客户端据此区分停服和网络异常
+func (c *Connection) Shutdown(code int, text string) {
+	select {
+	case c.closing <- websocket.FormatCloseMessage(code, text):
+	default:
+	}
+}
+
+// Done 返回连接关闭信号
+func (c *Connection) Done() <-chan struct{} {
+	return c.done
+}
+
+// writePump 是该连接唯一的写协程
+func (c *Connection) writePump() {
+	logger := config.GetZapLogger()
+	writeWait := viper.GetDuration("websocket.writeWait")
+	ticker := time.NewTicker(viper.GetDuration("websocket.pingPeriod"))
+	defer func() {
+		ticker.Stop()
+		c.Close()
+	}()
+
+	for {
+		select {
+		case message := <-c.send:
+			if err := c.write(message, writeWait); err != nil {
+				logger.Warn("发送消息失败: " + err.Error())
+				return
+			}
+		case frame := <-c.closing:
+			for len(c.send) > 0 {
+				if err := c.write(<-c.send, writeWait); err != nil {
+					logger.Warn("发送消息失败: " + err.Error())
+					return
+				}
+			}
+			if err := c.ws.WriteControl(websocket.CloseMessage, frame, time.Now().Add(writeWait)); err != nil {
+				logger.Warn("发送关闭帧失败: " + err.Error())
+			}
+			return
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				logger.Warn("发送心跳失败: " + err.Error())
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// write 编码并写出一条消息，编码失败的消息直接丢弃
+func (c *Connection) write(message model.Message, writeWait time.Duration) error {
+	data, err := c.codec.Encode(message)
+	if err != nil {
+		config.GetZapLogger().Error("编码消息失败: " + message.Type + ": " + err.Error())
+		return nil
+	}
+	c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.ws.WriteMessage(c.codec.FrameType(), data)
+}